@@ -66,40 +66,46 @@ What is the sum of all of the calibration values?
 package Day1
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"io"
 	"strconv"
 	"unicode"
 )
 
-func Day1() [2]int {
-	return [2]int{
-		d1p1(),
-		d1p2(),
-	}
+func init() {
+	solver.Register(1, solver.New(Parse, Part1, Part2))
 }
 
-func d1p1() int {
-	//Step 1 open and scan the file
-	file, err := os.Open("./Day1/Ressources/day1_input.txt")
+// Input is the calibration document, one string per line
+type Input []string
+
+// Parse reads the calibration document line by line
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
+	lines := Input{}
 
-	if err != nil {
-		log.Fatal(err)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
 	}
 
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 
-	scanner := bufio.NewScanner(file)
+	return lines, nil
+}
+
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
 	sum := 0
 
-	for scanner.Scan() {
+	for lineNumber, line := range input {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		//find first and last digit in the string
-		chars := []rune(scanner.Text())
+		chars := []rune(line)
 		firstDigit := rune(0)
 		lastDigit := rune(0)
 
@@ -117,22 +123,17 @@ func d1p1() int {
 
 		//convert the 2 single digits strings into one number
 		doubleDigit, err := strconv.Atoi(string(firstDigit) + string(lastDigit))
-
 		if err != nil {
-			log.Fatal(err)
+			return solver.Result{}, solver.Errorf(lineNumber+1, 0, "no digit found in line: %q", line)
 		}
 
 		sum += doubleDigit
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
-	return sum
+	return solver.Int(sum), nil
 }
 
-func d1p2() int {
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
 	lookup := map[string]rune{
 		"one":   '1',
 		"two":   '2',
@@ -145,26 +146,15 @@ func d1p2() int {
 		"nine":  '9',
 	}
 
-	//step 1 parse the file and scan it
-	file, err := os.Open("./Day1/Ressources/day1_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
 	sum := 0
 
 	//look for text matching lookup table or digit
 	//convert the textNumbers to digit
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		chars := []rune(scanner.Text())
+	for lineNumber, line := range input {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
+		chars := []rune(line)
 		firstDigit := rune(0)
 		lastDigit := rune(0)
 
@@ -196,15 +186,11 @@ func d1p2() int {
 
 		doubleDigit, err := strconv.Atoi(string(firstDigit) + string(lastDigit))
 		if err != nil {
-			log.Fatal(err)
+			return solver.Result{}, solver.Errorf(lineNumber+1, 0, "no digit or spelled digit found in line: %q", line)
 		}
 
 		sum += doubleDigit
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
-	return sum
+	return solver.Int(sum), nil
 }
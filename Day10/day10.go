@@ -215,239 +215,252 @@ Figure out whether you have time to search for the nest by calculating the area
 package Day10
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
 )
 
-type cell struct {
-	pos      [2]int
-	canUp    bool
-	canDown  bool
-	canLeft  bool
-	canRight bool
-	dist     int
-	char     rune
-	visited  bool
-	enclosed bool
-}
+// directions a pipe connects to, a tile is the set of its connections
+const (
+	up uint8 = 1 << iota
+	down
+	left
+	right
+)
 
-var asciiTable = map[rune]cell{
-	'|': {
-		canUp:   true,
-		canDown: true,
-	},
-	'-': {
-		canLeft:  true,
-		canRight: true,
-	},
-	'L': {
-		canUp:    true,
-		canRight: true,
-	},
-	'J': {
-		canLeft: true,
-		canUp:   true,
-	},
-	'7': {
-		canLeft: true,
-		canDown: true,
-	},
-	'F': {
-		canDown:  true,
-		canRight: true,
-	},
-	'.': {},
-	'S': {},
+// opposite direction of each direction
+var opposite = map[uint8]uint8{up: down, down: up, left: right, right: left}
+
+// move of each direction, {x, y} with y going down
+var moves = map[uint8][2]int{up: {0, -1}, down: {0, 1}, left: {-1, 0}, right: {1, 0}}
+
+var asciiTable = map[rune]uint8{
+	'|': up | down,
+	'-': left | right,
+	'L': up | right,
+	'J': up | left,
+	'7': down | left,
+	'F': down | right,
+	'.': 0,
+	'S': 0,
 }
 
-func Day10() [2]int {
-	return [2]int{
-		d10p1(),
-		d10p2(),
+// Tile is the place of a cell compared to the main loop
+type Tile int
+
+const (
+	Outside Tile = iota
+	Inside
+	OnLoop
+)
+
+func (t Tile) String() string {
+	switch t {
+	case Outside:
+		return "outside"
+	case Inside:
+		return "inside"
+	case OnLoop:
+		return "loop"
 	}
+	return "Tile(" + strconv.Itoa(int(t)) + ")"
 }
 
-/*
-scan the maze and return:
-- grid map[[2]int]*cell
-- startPos [2]int
-- maxDist int
-- maxX
-- maxY
-*/
-func ScanMazeForMainLoop() (map[[2]int]*cell, [2]int, int, int, int) {
-	file, err := os.Open("./Day10/Ressources/day10_input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
+func init() {
+	solver.Register(10, solver.New(Parse, Part1, Part2))
+}
 
-	scanner := bufio.NewScanner(file)
+// Input is the sketch of the pipes, one row of tiles per line
+type Input [][]rune
 
-	grid := map[[2]int]*cell{}
-	startPos := [2]int{-1, -1}
-	maxX := 0
-	maxY := 0
-	y := 0
+// Parse reads the sketch and check that it has a starting tile
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
+	rows := Input{}
+	hasStart := false
 
 	for scanner.Scan() {
 		row := []rune(scanner.Text())
-		for x := 0; x < len(row); x++ {
-			if x > maxX {
-				maxX = x
-			}
-			if y > maxY {
-				maxY = y
-			}
-
-			//convert input char to cell struct
-			newCell := cell{
-				pos:      [2]int{x, y},
-				canUp:    asciiTable[row[x]].canUp,
-				canDown:  asciiTable[row[x]].canDown,
-				canLeft:  asciiTable[row[x]].canLeft,
-				canRight: asciiTable[row[x]].canRight,
-				char:     row[x],
+		for x, char := range row {
+			if _, ok := asciiTable[char]; !ok {
+				return nil, solver.Errorf(len(rows)+1, x+1, "unknown tile %q", char)
 			}
-
-			if newCell.char == 'S' {
-				startPos = newCell.pos
+			if char == 'S' {
+				hasStart = true
 			}
-			grid[newCell.pos] = &newCell
 		}
-		y++
+		rows = append(rows, row)
 	}
 
-	//checking left neighbour's right connection to see if we can go left from start
-	if v, ok := grid[[2]int{startPos[0] - 1, startPos[1]}]; ok {
-		if v.canRight {
-			grid[startPos].canLeft = true
-		}
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
 
-	//checking right neighbour's left connection to see if we can go right from start
+	if !hasStart {
+		return nil, errors.New("no starting tile S found in the sketch")
+	}
 
-	if v, ok := grid[[2]int{startPos[0] + 1, startPos[1]}]; ok {
-		if v.canLeft {
-			grid[startPos].canRight = true
-		}
+	return rows, nil
+}
+
+// tile at x, y, the cells out of the sketch are ground
+func (in Input) at(x, y int) rune {
+	if y < 0 || y >= len(in) || x < 0 || x >= len(in[y]) {
+		return '.'
 	}
+	return in[y][x]
+}
 
-	//checking up neighbour's down connection to see if we can go up from start
-	if v, ok := grid[[2]int{startPos[0], startPos[1] - 1}]; ok {
-		if v.canDown {
-			grid[startPos].canUp = true
+// InferStart finds the starting tile and the pipe hidden under it,
+// the pipe connects to the two neighbours that connect back to the start
+func InferStart(input Input) ([2]int, rune, error) {
+	start := [2]int{-1, -1}
+	for y, row := range input {
+		for x, char := range row {
+			if char == 'S' {
+				start = [2]int{x, y}
+			}
 		}
 	}
+	if start[0] == -1 {
+		return start, 0, errors.New("no starting tile S found in the sketch")
+	}
 
-	//checking down neighbour's up connection to see if we can go down from start
-	if v, ok := grid[[2]int{startPos[0], startPos[1] + 1}]; ok {
-		if v.canUp {
-			grid[startPos].canDown = true
+	var connections uint8
+	for _, dir := range []uint8{up, down, left, right} {
+		m := moves[dir]
+		if asciiTable[input.at(start[0]+m[0], start[1]+m[1])]&opposite[dir] != 0 {
+			connections |= dir
 		}
 	}
 
-	maxDist := 0
-	currentPos := [][2]int{startPos}
-
-	for i := 0; i < len(currentPos); i++ {
-		grid[currentPos[i]].visited = true
-		hasMoved := false
-
-		//moving down
-		if grid[currentPos[i]].canDown {
-			newPos := [2]int{currentPos[i][0], currentPos[i][1] + 1}
-			if !grid[newPos].visited {
-				grid[newPos].dist = grid[currentPos[i]].dist + 1
-				currentPos = append(currentPos, newPos)
-				if grid[newPos].dist > maxDist {
-					maxDist = grid[newPos].dist
-				}
-				hasMoved = true
-			}
+	for char, pipe := range asciiTable {
+		if char != 'S' && pipe == connections && bits.OnesCount8(pipe) == 2 {
+			return start, char, nil
 		}
+	}
+	return start, 0, fmt.Errorf("the start at %v connects to %d neighbours, expected 2", start, bits.OnesCount8(connections))
+}
 
-		//moving up
-		if grid[currentPos[i]].canUp {
-			newPos := [2]int{currentPos[i][0], currentPos[i][1] - 1}
-			if !grid[newPos].visited {
-				grid[newPos].dist = grid[currentPos[i]].dist + 1
-				currentPos = append(currentPos, newPos)
-				if grid[newPos].dist > maxDist {
-					maxDist = grid[newPos].dist
-				}
-				hasMoved = true
-			}
-		}
+// Loop is the main loop, Path is every tile of the loop in the order of the walk
+// starting from Start, it is the list of the vertices of a polygon
+type Loop struct {
+	Start      [2]int
+	StartShape rune
+	Path       [][2]int
+}
 
-		//moving left
-		if grid[currentPos[i]].canLeft {
-			newPos := [2]int{currentPos[i][0] - 1, currentPos[i][1]}
-			if !grid[newPos].visited {
-				grid[newPos].dist = grid[currentPos[i]].dist + 1
-				currentPos = append(currentPos, newPos)
-				if grid[newPos].dist > maxDist {
-					maxDist = grid[newPos].dist
-				}
-				hasMoved = true
+// FindLoop infers the start pipe and follows the loop until it is back to the start
+func FindLoop(ctx context.Context, input Input) (Loop, error) {
+	start, shape, err := InferStart(input)
+	if err != nil {
+		return Loop{}, err
+	}
+	loop := Loop{Start: start, StartShape: shape}
 
-			}
+	pipeAt := func(pos [2]int) uint8 {
+		if pos == start {
+			return asciiTable[shape]
 		}
+		return asciiTable[input.at(pos[0], pos[1])]
+	}
 
-		//moving right
-		if grid[currentPos[i]].canRight {
-			newPos := [2]int{currentPos[i][0] + 1, currentPos[i][1]}
-			if !grid[newPos].visited {
-				grid[newPos].dist = grid[currentPos[i]].dist + 1
-				currentPos = append(currentPos, newPos)
-				if grid[newPos].dist > maxDist {
-					maxDist = grid[newPos].dist
-				}
-				hasMoved = true
+	//leave the start by its first connection, then always leave a tile
+	//by the connection we did not come from
+	pos := start
+	var from uint8
+	for {
+		if len(loop.Path)%(1<<12) == 0 {
+			if err := ctx.Err(); err != nil {
+				return Loop{}, err
 			}
 		}
-
-		//remove positions that are not able to progress (next to validated or dead ends)
-		if !hasMoved {
-			currentPos[i], currentPos[len(currentPos)-1] = currentPos[len(currentPos)-1], currentPos[i]
-			currentPos = currentPos[:len(currentPos)-1]
-			i--
+		loop.Path = append(loop.Path, pos)
+
+		pipe := pipeAt(pos) &^ from
+		dir := pipe & -pipe
+		m := moves[dir]
+		next := [2]int{pos[0] + m[0], pos[1] + m[1]}
+		if pipeAt(next)&opposite[dir] == 0 {
+			return Loop{}, fmt.Errorf("the pipe at %v leads to %v which does not connect back", pos, next)
+		}
+		if next == start {
+			return loop, nil
 		}
+		pos, from = next, opposite[dir]
 	}
+}
 
-	return grid, startPos, maxDist, maxX, maxY
+// Area is the area of the polygon of the loop, with the shoelace formula
+func (l Loop) Area() int {
+	twice := 0
+	for i, a := range l.Path {
+		b := l.Path[(i+1)%len(l.Path)]
+		twice += a[0]*b[1] - b[0]*a[1]
+	}
+	if twice < 0 {
+		twice = -twice
+	}
+	return twice / 2
 }
 
-func d10p1() int {
-	_, _, maxDist, _, _ := ScanMazeForMainLoop()
-	return maxDist
+// Interior counts the tiles enclosed by the loop with Pick's theorem:
+// Area = inside + boundary/2 - 1 and the boundary points are the tiles of the loop
+func (l Loop) Interior() int {
+	return l.Area() - len(l.Path)/2 + 1
 }
 
-func d10p2() int {
-	//we first do the extact same thing as part 1 as we need to map the loop
-	grid, _, _, maxX, maxY := ScanMazeForMainLoop()
-	enclosedCount := 0
-
-	//check all the "non-visited" (loop parts) cells
-	//look to the left of them and count the number of parts going up
-	//if odd then assume it's enclosed
-	for y := 0; y <= maxY; y++ {
-		for x := 0; x <= maxX; x++ {
-			if grid[[2]int{x, y}].visited {
+// Classify tells for every cell of the sketch if it is on the loop, inside or outside.
+// A row is scanned from the left, each tile of the loop connected upward
+// is a crossing of the loop, the cells after an odd number of crossings are inside
+func (l Loop) Classify(input Input) [][]Tile {
+	onLoop := make(map[[2]int]bool, len(l.Path))
+	for _, pos := range l.Path {
+		onLoop[pos] = true
+	}
+
+	tiles := make([][]Tile, len(input))
+	for y, row := range input {
+		tiles[y] = make([]Tile, len(row))
+		inside := false
+		for x, char := range row {
+			pos := [2]int{x, y}
+			if !onLoop[pos] {
+				if inside {
+					tiles[y][x] = Inside
+				}
 				continue
 			}
-			leftCounter := 0
-			for x2 := 0; x2 < x; x2++ {
-				if grid[[2]int{x2, y}].visited && grid[[2]int{x2, y}].canUp {
-					leftCounter++
-				}
+			tiles[y][x] = OnLoop
+			if pos == l.Start {
+				char = l.StartShape
 			}
-			if leftCounter%2 != 0 && leftCounter != 0 {
-				grid[[2]int{x, y}].enclosed = true
-				enclosedCount++
+			if asciiTable[char]&up != 0 {
+				inside = !inside
 			}
 		}
 	}
+	return tiles
+}
 
-	return enclosedCount
+// the farthest tile of the loop is halfway around it
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	loop, err := FindLoop(ctx, input)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(len(loop.Path) / 2), nil
+}
+
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	loop, err := FindLoop(ctx, input)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(loop.Interior()), nil
 }
@@ -136,37 +136,41 @@ What is the sum of these lengths?
 package Day11
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
+	"context"
+	"errors"
+	"io"
 	"math"
-	"os"
 )
 
-func Day11() [2]int {
-	return [2]int{
-		d11p1(),
-		d11p2(),
-	}
+func init() {
+	solver.Register(11, solver.New(Parse, Part1, Part2))
 }
 
-func GetShortestDistordedDistance(distortion int) int {
-	file, err := os.Open("./Day11/Ressources/day11_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
+// Input is the image of the universe, one row of the star chart per line
+type Input [][]rune
 
-	scanner := bufio.NewScanner(file)
+// Parse reads all the rows of the star chart
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
 
 	//get all data from input file
-	starChart := [][]rune{}
+	starChart := Input{}
 	for scanner.Scan() {
 		starChart = append(starChart, []rune(scanner.Text()))
 	}
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return nil, scanner.Err()
+	}
+	if len(starChart) == 0 {
+		return nil, errors.New("empty star chart")
 	}
 
+	return starChart, nil
+}
+
+func GetShortestDistordedDistance(ctx context.Context, starChart Input, distortion int) (int, error) {
 	//find all rows that doesn't have a '#'
 	rowsWithoutHash := []int{}
 	for y := 0; y < len(starChart); y++ {
@@ -225,19 +229,30 @@ func GetShortestDistordedDistance(distortion int) int {
 	//get the sum of shortest path
 	sum := 0
 	for i := 0; i < len(starPos)-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		for j := i + 1; j < len(starPos); j++ {
 			sum += ManhattanDistance(starPos[i], starPos[j])
 		}
 	}
-	return sum
+	return sum, nil
 }
 
-func d11p1() int {
-	return GetShortestDistordedDistance(2)
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	sum, err := GetShortestDistordedDistance(ctx, input, 2)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(sum), nil
 }
 
-func d11p2() int {
-	return GetShortestDistordedDistance(1000000)
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	sum, err := GetShortestDistordedDistance(ctx, input, 1000000)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(sum), nil
 }
 
 // calculate the Manhattan Distance between A and B and return the distance
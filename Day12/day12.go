@@ -149,148 +149,186 @@ Unfold your condition records; what is the new sum of possible arrangement count
 package Day12
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"io"
 	"strconv"
 	"strings"
 )
 
-func Day12() [2]int {
-	return [2]int{
-		d12p1(),
-		d12p2(),
-	}
+func init() {
+	solver.Register(12, solver.New(Parse, Part1, Part2))
 }
 
 type inputData struct {
 	puzzle     string
 	clues      []int
 	clueString string
-	ways       int
 }
 
-func loadInput(path string) []inputData {
-	file, err := os.Open(path)
-	if err != nil {
-		log.Fatal(err)
-	}
+// Input is the condition records, one row of springs and its clues per line
+type Input []inputData
 
-	scanner := bufio.NewScanner(file)
-	data := []inputData{}
+// Parse reads every condition record
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
+	data := Input{}
 
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		split := strings.Split(scanner.Text(), " ")
+		if len(split) != 2 {
+			return nil, solver.Errorf(lineNumber, 0, "expected springs and clues in line: %q", scanner.Text())
+		}
+		for x, r := range split[0] {
+			if r != '.' && r != '#' && r != '?' {
+				return nil, solver.Errorf(lineNumber, x+1, "unknown spring %q", r)
+			}
+		}
 		cluesPart := strings.Split(split[1], ",")
 		cluesInt := make([]int, len(cluesPart))
 
+		//column of the current clue, after the springs and the space
+		column := len(split[0]) + 2
 		for i, s := range cluesPart {
 			v, err := strconv.Atoi(s)
 			if err != nil {
-				log.Fatal(err)
+				return nil, solver.NewParseError(lineNumber, column, err)
 			}
 			cluesInt[i] = v
+			column += len(s) + 1
 		}
 
 		newData := inputData{
 			puzzle:     split[0],
 			clues:      cluesInt,
 			clueString: split[1],
-			ways:       -1,
 		}
 
 		data = append(data, newData)
 	}
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return nil, scanner.Err()
 	}
 
-	return data
+	return data, nil
 }
 
-func d12p1() int {
-	loadedData := loadInput("./Day12/Ressources/day12_input.txt")
-	//map of puzzle => map of clue => ways
+// sum the arrangements of every record once unfolded the given number of times
+func sumArrangements(ctx context.Context, input Input, times int) (int, error) {
 	sum := 0
-	for _, data := range loadedData {
-		permutations := generatePermutations(data.puzzle)
-		for _, p := range permutations {
-			if evaluateValidity(p, data) {
-				sum++
-			}
+	for _, data := range input {
+		if err := ctx.Err(); err != nil {
+			return 0, err
 		}
+		sum += countArrangements(data.unfold(times))
 	}
+	return sum, nil
+}
 
-	return sum
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	sum, err := sumArrangements(ctx, input, 1)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(sum), nil
 }
 
-func d12p2() int {
-	return 0
+// part 2, the records are unfolded 5 times before counting
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	sum, err := sumArrangements(ctx, input, 5)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(sum), nil
 }
 
-func generatePermutations(s string) []string {
-	var result []string
+// unfold a record: the springs are repeated with a '?' between each copy
+// and the clues are repeated as they are
+func (d inputData) unfold(times int) inputData {
+	if times <= 1 {
+		return d
+	}
+	puzzles := make([]string, times)
+	clueStrings := make([]string, times)
+	clues := make([]int, 0, len(d.clues)*times)
+	for i := 0; i < times; i++ {
+		puzzles[i] = d.puzzle
+		clueStrings[i] = d.clueString
+		clues = append(clues, d.clues...)
+	}
+	return inputData{
+		puzzle:     strings.Join(puzzles, "?"),
+		clues:      clues,
+		clueString: strings.Join(clueStrings, ","),
+	}
+}
 
-	var backtrack func(index int, current string)
-	backtrack = func(index int, current string) {
-		if index == len(s) {
-			result = append(result, current)
-			return
+// count the ways to replace the '?' so that the damaged runs match the clues.
+// The state is (position in the springs, index of the current clue, length of the
+// current run of '#'), each state is computed once and kept in memo
+func countArrangements(d inputData) int {
+	maxRun := 0
+	for _, c := range d.clues {
+		if c > maxRun {
+			maxRun = c
 		}
+	}
 
-		if s[index] == '?' {
-			backtrack(index+1, current+".")
-			backtrack(index+1, current+"#")
-		} else {
-			backtrack(index+1, current+string(s[index]))
+	//memo[pos][clue][run], -1 when not computed yet
+	nClues := len(d.clues)
+	memo := make([][][]int, len(d.puzzle))
+	for pos := range memo {
+		memo[pos] = make([][]int, nClues+1)
+		for clue := range memo[pos] {
+			memo[pos][clue] = make([]int, maxRun+1)
+			for run := range memo[pos][clue] {
+				memo[pos][clue][run] = -1
+			}
 		}
 	}
 
-	backtrack(0, "")
-	return result
-}
-
-var validityCache = make(map[string]bool)
-
-func evaluateValidity(s string, data inputData) bool {
-	cacheKey := s + "|" + data.clueString
+	var count func(pos, clue, run int) int
+	count = func(pos, clue, run int) int {
+		//end of the springs, every clue must be used and the last run must be closed
+		if pos == len(d.puzzle) {
+			if run == 0 && clue == nClues {
+				return 1
+			}
+			if run > 0 && clue == nClues-1 && run == d.clues[clue] {
+				return 1
+			}
+			return 0
+		}
+		if v := memo[pos][clue][run]; v != -1 {
+			return v
+		}
 
-	if v, ok := validityCache[cacheKey]; ok {
-		return v
-	}
+		ways := 0
+		spring := d.puzzle[pos]
 
-	c := getSharpCount(s)
-	if len(c) != len(data.clues) {
-		validityCache[cacheKey] = false
-		return false
-	}
-	for i := 0; i < len(c); i++ {
-		if c[i] != data.clues[i] {
-			validityCache[cacheKey] = false
-			return false
+		//damaged spring, the current run goes on if the clue allows it
+		if spring == '#' || spring == '?' {
+			if clue < nClues && run < d.clues[clue] {
+				ways += count(pos+1, clue, run+1)
+			}
 		}
-	}
-	validityCache[cacheKey] = true
-	return true
-}
-
-func getSharpCount(s string) []int {
-	count := []int{}
-	sharpCount := 0
 
-	for _, r := range s {
-		if r == '#' {
-			sharpCount++
-		} else if r == '.' && sharpCount > 0 {
-			count = append(count, sharpCount)
-			sharpCount = 0
+		//operational spring, it closes the current run if the run has the length of its clue
+		if spring == '.' || spring == '?' {
+			if run == 0 {
+				ways += count(pos+1, clue, 0)
+			} else if run == d.clues[clue] {
+				ways += count(pos+1, clue+1, 0)
+			}
 		}
-	}
 
-	if sharpCount > 0 {
-		count = append(count, sharpCount)
+		memo[pos][clue][run] = ways
+		return ways
 	}
 
-	return count
+	return count(0, 0, 0)
 }
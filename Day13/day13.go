@@ -159,17 +159,15 @@ What number do you get after summarizing the new reflection line in each pattern
 package Day13
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
+	"context"
+	"io"
 	"math"
-	"os"
 )
 
-func Day13() [2]int {
-	return [2]int{
-		d13p1(),
-		d13p2(),
-	}
+func init() {
+	solver.Register(13, solver.New(Parse, Part1, Part2))
 }
 
 type Puzzle struct {
@@ -178,26 +176,20 @@ type Puzzle struct {
 	vertSym   []int
 }
 
-func loadDataFromInput(path string) []Puzzle {
-	file, err := os.Open(path)
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	if err != nil {
-		log.Fatal(err)
-	}
+// Input is the list of patterns of ash and rocks
+type Input []Puzzle
 
-	scanner := bufio.NewScanner(file)
+// Parse reads the patterns, they are separated by an empty line
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
 
-	puzzles := []Puzzle{}
+	puzzles := Input{}
 	puzzle := Puzzle{}
 	inPuzzle := false
+	lineNumber := 0
 
 	for scanner.Scan() {
+		lineNumber++
 		if scanner.Text() == "" {
 			if inPuzzle {
 				puzzles = append(puzzles, puzzle)
@@ -206,6 +198,14 @@ func loadDataFromInput(path string) []Puzzle {
 			}
 		} else {
 			line := []rune(scanner.Text())
+			for x, r := range line {
+				if r != '.' && r != '#' {
+					return nil, solver.Errorf(lineNumber, x+1, "unknown cell %q", r)
+				}
+			}
+			if inPuzzle && len(line) != len(puzzle.cells[0]) {
+				return nil, solver.Errorf(lineNumber, 0, "expected %d cells in line, found %d", len(puzzle.cells[0]), len(line))
+			}
 			puzzle.cells = append(puzzle.cells, line)
 
 			if !inPuzzle {
@@ -219,26 +219,32 @@ func loadDataFromInput(path string) []Puzzle {
 	}
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return nil, scanner.Err()
 	}
 
-	return puzzles
+	return puzzles, nil
 }
 
-func d13p1() int {
-	puzzles := loadDataFromInput("./Day13/Ressources/day13_input.txt")
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	puzzles := input
 	total := 0
 	for i := 0; i < len(puzzles); i++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		_, r := calculateFirstReflection(puzzles[i])
 		total += r
 	}
-	return total
+	return solver.Int(total), nil
 }
 
-func d13p2() int {
-	puzzles := loadDataFromInput("./Day13/Ressources/day13_input.txt")
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	puzzles := input
 	total := 0
 	for i := 0; i < len(puzzles); i++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		//get V1 of puzzle
 		p, _ := calculateFirstReflection(puzzles[i])
 		foundSmudge := false
@@ -286,7 +292,7 @@ func d13p2() int {
 		}
 	}
 
-	return total
+	return solver.Int(total), nil
 }
 
 func calculateFirstReflection(puzzle Puzzle) (Puzzle, int) {
@@ -142,126 +142,176 @@ north support beams?
 package Day14
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"errors"
+	"io"
+	"strings"
 )
 
-func Day14() [2]int {
-	return [2]int{
-		d14p1(),
-		d14p2(),
-	}
+func init() {
+	solver.Register(14, solver.New(Parse, Part1, Part2))
 }
 
-func loadData(path string) ([][]bool, [][2]int) {
-	file, err := os.Open(path)
-
-	if err != nil {
-		log.Fatal(err)
-	}
+// Platform is the grid of the platform, row by row: rounded rocks 'O',
+// cube rocks '#' and empty spaces '.'
+type Platform [][]byte
 
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+// Input is the platform as read from the puzzle input
+type Input = Platform
 
-	scanner := bufio.NewScanner(file)
+// Parse reads the platform, every line must have the same width
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
 
-	grid := [][]bool{}
-	rocks := [][2]int{}
+	platform := Platform{}
 	y := 0
 	for scanner.Scan() {
-		x := 0
-		grid = append(grid, []bool{})
-
-		for _, c := range scanner.Text() {
-
-			if c == '.' {
-				//new cell free
-				grid[y] = append(grid[y], true)
-				x++
-			} else if c == '#' {
-				//new cell non free
-				grid[y] = append(grid[y], false)
-				x++
-			} else if c == 'O' {
-				//new rock && new cell
-				grid[y] = append(grid[y], false)
-				rocks = append(rocks, [2]int{y, x})
-				x++
+		row := []byte(scanner.Text())
+		for x, c := range row {
+			if c != '.' && c != '#' && c != 'O' {
+				return nil, solver.Errorf(y+1, x+1, "unknown cell %q", c)
 			}
 		}
+		if y > 0 && len(row) != len(platform[0]) {
+			return nil, solver.Errorf(y+1, 0, "expected %d cells, found %d", len(platform[0]), len(row))
+		}
+		platform = append(platform, row)
 		y++
 	}
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return nil, scanner.Err()
 	}
 
-	return grid, rocks
+	return platform, nil
 }
 
-func d14p1() int {
-	grid, rocks := loadData("./Day14/Ressources/day14_input.txt")
-	gravity := [2]int{-1, 0}
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	platform := input.clone()
+	if err := platform.Tilt([2]int{-1, 0}); err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(platform.Load()), nil
+}
 
-	tilt(grid, rocks, gravity)
+// number of spin cycles of part 2, given by the puzzle
+const part2Cycles = 1000000000
 
-	sum := 0
-	for i := 0; i < len(rocks); i++ {
-		sum += len(grid) - rocks[i][0]
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	platform, err := SpinCycles(ctx, input, part2Cycles)
+	if err != nil {
+		return solver.Result{}, err
 	}
-	return sum
+	return solver.Int(platform.Load()), nil
 }
 
-func d14p2() int {
-	grid, rocks := loadData("./Day14/Ressources/day14_input.txt")
+// spin cycle: tilt north, west, south then east
+var spinCycle = [][2]int{{-1, 0}, {0, -1}, {1, 0}, {0, 1}}
 
-	//big cycle that endup looping over the same value, no need to run all billion times
-	//1000 is enough to get the result, 100 is not
-	for i := 0; i < 1000; i++ {
-		tilt(grid, rocks, [2]int{-1, 0}) //North
-		tilt(grid, rocks, [2]int{0, -1}) //West
-		tilt(grid, rocks, [2]int{1, 0})  //south
-		tilt(grid, rocks, [2]int{0, 1})  //East
+// SpinCycles returns the platform after n spin cycles, the input is not modified.
+// The state after each cycle is kept by its text, once a state comes back the
+// cycles loop and the state after n cycles is picked in the loop
+func SpinCycles(ctx context.Context, input Platform, n int) (Platform, error) {
+	platform := input.clone()
+	seen := map[string]int{platform.String(): 0}
+	history := []string{platform.String()}
+
+	for i := 1; i <= n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, gravity := range spinCycle {
+			if err := platform.Tilt(gravity); err != nil {
+				return nil, err
+			}
+		}
+
+		key := platform.String()
+		if first, ok := seen[key]; ok {
+			period := i - first
+			return parsePlatform(history[first+(n-first)%period]), nil
+		}
+		seen[key] = i
+		history = append(history, key)
 	}
+	return platform, nil
+}
 
+// Tilt moves every rounded rock as far as it can along the gravity vector (dy, dx),
+// in a single pass. The cells form lines along the gravity, each line is walked
+// from its downhill end keeping the next free cell where a rock lands
+func (p Platform) Tilt(gravity [2]int) error {
+	if gravity == [2]int{0, 0} {
+		return errors.New("gravity vector cannot be null")
+	}
+
+	for y := range p {
+		for x := range p[y] {
+			//a line ends where the next cell along the gravity is out of the platform
+			if p.isInGrid([2]int{y + gravity[0], x + gravity[1]}) {
+				continue
+			}
+			free := [2]int{y, x}
+			for pos := free; p.isInGrid(pos); pos = [2]int{pos[0] - gravity[0], pos[1] - gravity[1]} {
+				switch p[pos[0]][pos[1]] {
+				case '#':
+					free = [2]int{pos[0] - gravity[0], pos[1] - gravity[1]}
+				case 'O':
+					p[pos[0]][pos[1]] = '.'
+					p[free[0]][free[1]] = 'O'
+					free = [2]int{free[0] - gravity[0], free[1] - gravity[1]}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Load is the total load on the north support beams
+func (p Platform) Load() int {
 	sum := 0
-	for i := 0; i < len(rocks); i++ {
-		sum += len(grid) - rocks[i][0]
+	for y, row := range p {
+		for _, c := range row {
+			if c == 'O' {
+				sum += len(p) - y
+			}
+		}
 	}
 	return sum
 }
 
-// check if a coordinate is in grid's bounds
-func isInGrid(pos [2]int, grid [][]bool) bool {
-	if pos[0] < 0 || pos[0] >= len(grid) || pos[1] < 0 || pos[1] >= len(grid[0]) {
-		return false
+// String prints the platform as in the puzzle input
+func (p Platform) String() string {
+	rows := make([]string, len(p))
+	for y, row := range p {
+		rows[y] = string(row)
 	}
-	return true
+	return strings.Join(rows, "\n")
 }
 
-// celular automata that move rocks if condition met
-func tilt(grid [][]bool, rocks [][2]int, gravity [2]int) {
-	hasAnyRockMoved := true
-
-	for hasAnyRockMoved {
-		hasAnyRockMoved = false
+// read back a platform printed by String
+func parsePlatform(text string) Platform {
+	platform := Platform{}
+	for _, row := range strings.Split(text, "\n") {
+		platform = append(platform, []byte(row))
+	}
+	return platform
+}
 
-		for i := 0; i < len(rocks); i++ {
-			currentPos := rocks[i]
-			nextPos := [2]int{
-				currentPos[0] + gravity[0],
-				currentPos[1] + gravity[1]}
+func (p Platform) clone() Platform {
+	out := make(Platform, len(p))
+	for y, row := range p {
+		out[y] = append([]byte{}, row...)
+	}
+	return out
+}
 
-			if isInGrid(nextPos, grid) && grid[nextPos[0]][nextPos[1]] {
-				grid[nextPos[0]][nextPos[1]] = false
-				grid[currentPos[0]][currentPos[1]] = true
-				rocks[i] = nextPos
-				hasAnyRockMoved = true
-			}
-		}
+// check if a coordinate is in grid's bounds
+func (p Platform) isInGrid(pos [2]int) bool {
+	if pos[0] < 0 || pos[0] >= len(p) || pos[1] < 0 || pos[1] >= len(p[0]) {
+		return false
 	}
+	return true
 }
@@ -219,26 +219,27 @@ lens configuration?
 package Day15
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"io"
 	"strconv"
 )
 
-func Day15() [2]int {
-	return [2]int{
-		d15p1(),
-		d15p2(),
-	}
+func init() {
+	solver.Register(15, solver.New(Parse, Part1, Part2))
 }
 
-func d15p1() int {
-	strs := loadData("./Day15/Ressources/day15_input.txt")
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	strs := input
 	sum := 0
 	for _, str := range strs {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		sum += hash(str)
 	}
-	return sum
+	return solver.Int(sum), nil
 }
 
 type Lense struct {
@@ -251,8 +252,8 @@ type Box struct {
 	lenses []Lense
 }
 
-func d15p2() int {
-	strs := loadData("./Day15/Ressources/day15_input.txt")
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	strs := input
 
 	//initialized the blocks
 	boxes := map[int]Box{}
@@ -262,7 +263,12 @@ func d15p2() int {
 		}
 	}
 
+	//the initialization sequence is a single line, column is where the current step starts
+	column := 1
 	for _, str := range strs {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		//step 1 convert string to lense
 		newLense := Lense{}
 		powerStr := ""
@@ -289,7 +295,7 @@ func d15p2() int {
 		if isEqual {
 			v, err := strconv.Atoi(powerStr)
 			if err != nil {
-				log.Fatal(err)
+				return solver.Result{}, solver.NewParseError(1, column+len(newLense.label)+1, err)
 			}
 			newLense.power = v
 		}
@@ -347,6 +353,7 @@ func d15p2() int {
 			}
 		}
 
+		column += len(str) + 1
 	}
 
 	//Step 3 compute final power
@@ -358,24 +365,17 @@ func d15p2() int {
 		}
 	}
 
-	return sum
+	return solver.Int(sum), nil
 }
 
-func loadData(path string) []string {
-	output := []string{}
+// Input is the initialization sequence, one string per comma separated step
+type Input []string
 
-	file, err := os.Open(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+// Parse splits the initialization sequence on commas, newlines are ignored
+func Parse(input io.Reader) (Input, error) {
+	output := Input{}
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(input)
 
 	currentBloc := []rune{}
 	for scanner.Scan() {
@@ -391,10 +391,10 @@ func loadData(path string) []string {
 	output = append(output, string(currentBloc))
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return nil, scanner.Err()
 	}
 
-	return output
+	return output, nil
 }
 
 func hash(str string) int {
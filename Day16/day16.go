@@ -137,17 +137,17 @@ how many tiles are energized in that configuration?
 package Day16
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"errors"
+	"io"
+	"sort"
 	"sync"
 )
 
-func Day16() [2]int {
-	return [2]int{
-		d16p1(),
-		d16p2(),
-	}
+func init() {
+	solver.Register(16, solver.New(Parse, Part1, Part2))
 }
 
 const (
@@ -168,19 +168,15 @@ type BeamHead struct {
 	position  Vector2
 }
 
-func loadData(path string) (int, int, map[Vector2]int) {
-	file, err := os.Open(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+// Input is the contraption layout with its width (xMax) and height (yMax)
+type Input struct {
+	xMax, yMax int
+	gridTypes  map[Vector2]int
+}
 
-	scanner := bufio.NewScanner(file)
+// Parse reads the contraption layout and convert each tile to its cell type
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
 	gridTypes := map[Vector2]int{}
 	y := 0
 	x := 0
@@ -204,6 +200,8 @@ func loadData(path string) (int, int, map[Vector2]int) {
 				newCell = splitter1
 			} else if r == '|' {
 				newCell = splitter2
+			} else {
+				return Input{}, solver.Errorf(y+1, x+1, "unknown tile %q", r)
 			}
 			gridTypes[p] = newCell
 			x++
@@ -212,87 +210,55 @@ func loadData(path string) (int, int, map[Vector2]int) {
 	}
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return Input{}, scanner.Err()
 	}
-	return x, y, gridTypes
+	return Input{xMax: x, yMax: y, gridTypes: gridTypes}, nil
 }
 
-func d16p1() int {
-	_, _, gridTypes := loadData("./Day16/Ressources/day16_input.txt")
-	gridState := map[Vector2]bool{}
-	copyGrid := map[Vector2]int{}
-	for k, v := range gridTypes {
-		copyGrid[k] = v
-		gridState[k] = false
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	_, count, err := SendBeam(ctx, input, Vector2{0, 0}, Vector2{1, 0})
+	if err != nil {
+		return solver.Result{}, err
 	}
-
-	return SendBeam(copyGrid, gridState, Vector2{0, 0}, Vector2{1, 0})
+	return solver.Int(count), nil
 }
 
-func d16p2() int {
-	xMax, yMax, gridTypes := loadData("./Day16/Ressources/day16_input.txt")
-	results := []int{}
-	// Limit the number of concurrent goroutines using a semaphore
-	maxConcurrent := 10 // Set the maximum number of concurrent goroutines
-	semaphore := make(chan struct{}, maxConcurrent)
+// part 2, best count of energized cells for a beam entering from any edge cell
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	xMax, yMax := input.xMax, input.yMax
 
-	// columns from 0 to xmax (inclusive)
-	var wg1 sync.WaitGroup
-	wg1.Add(xMax)
+	//every edge cell with the direction going into the contraption
+	entries := []BeamHead{}
 	for x := 0; x < xMax; x++ {
-		semaphore <- struct{}{} // Acquire semaphore
-		go func(x int) {
-			defer func() { <-semaphore }() // Release semaphore
-			defer wg1.Done()
-
-			gridState := map[Vector2]bool{}
-			copyGrid := map[Vector2]int{}
-			for k, v := range gridTypes {
-				copyGrid[k] = v
-				gridState[k] = false
-			}
-			results = append(results, SendBeam(copyGrid, gridState, Vector2{x, 0}, Vector2{0, 1}))
-
-			gridState = map[Vector2]bool{}
-			copyGrid = map[Vector2]int{}
-			for k, v := range gridTypes {
-				copyGrid[k] = v
-				gridState[k] = false
-			}
-			results = append(results, SendBeam(copyGrid, gridState, Vector2{x, yMax - 1}, Vector2{0, -1}))
-		}(x)
+		entries = append(entries, BeamHead{Vector2{0, 1}, Vector2{x, 0}}, BeamHead{Vector2{0, -1}, Vector2{x, yMax - 1}})
 	}
-	wg1.Wait()
-
-	// rows from 0 to ymax (inclusive)
-	var wg2 sync.WaitGroup
-	wg2.Add(yMax)
 	for y := 0; y < yMax; y++ {
-		semaphore <- struct{}{} // Acquire semaphore
-		go func(y int) {
-			defer func() { <-semaphore }() // Release semaphore
-			defer wg2.Done()
+		entries = append(entries, BeamHead{Vector2{1, 0}, Vector2{0, y}}, BeamHead{Vector2{-1, 0}, Vector2{xMax - 1, y}})
+	}
 
-			gridState := map[Vector2]bool{}
-			copyGrid := map[Vector2]int{}
-			for k, v := range gridTypes {
-				copyGrid[k] = v
-				gridState[k] = false
-			}
+	//each goroutine writes the slots of its own entry only
+	results := make([]int, len(entries))
+	errs := make([]error, len(entries))
 
-			results = append(results, SendBeam(copyGrid, gridState, Vector2{0, y}, Vector2{1, 0}))
+	// Limit the number of concurrent goroutines using a semaphore
+	maxConcurrent := 10 // Set the maximum number of concurrent goroutines
+	semaphore := make(chan struct{}, maxConcurrent)
 
-			gridState = map[Vector2]bool{}
-			copyGrid = map[Vector2]int{}
-			for k, v := range gridTypes {
-				copyGrid[k] = v
-				gridState[k] = false
-			}
+	var wg sync.WaitGroup
+	wg.Add(len(entries))
+	for i := range entries {
+		semaphore <- struct{}{} // Acquire semaphore
+		go func(i int) {
+			defer func() { <-semaphore }() // Release semaphore
+			defer wg.Done()
+			_, results[i], errs[i] = SendBeam(ctx, input, entries[i].position, entries[i].direction)
+		}(i)
+	}
+	wg.Wait()
 
-			results = append(results, SendBeam(copyGrid, gridState, Vector2{xMax - 1, y}, Vector2{-1, 0}))
-		}(y)
+	if err := errors.Join(errs...); err != nil {
+		return solver.Result{}, err
 	}
-	wg2.Wait()
 
 	max := 0
 	for _, r := range results {
@@ -301,97 +267,69 @@ func d16p2() int {
 		}
 	}
 
-	return max
-}
-
-func updateBeamHead(beamHead *BeamHead, dir Vector2, pos Vector2) {
-	beamHead.direction = dir
-	beamHead.position = pos
+	return solver.Int(max), nil
 }
 
-func updateCell(state *map[Vector2]bool, pos Vector2) int {
-	if !(*state)[pos] {
-		(*state)[pos] = true
-		return 1
+// directions of the beam leaving a cell of type cell that it entered going in direction dir
+func deflect(cell int, dir Vector2) []Vector2 {
+	switch cell {
+	case mirror1: // '/'
+		return []Vector2{{-dir.Y, -dir.X}}
+	case mirror2: // '\'
+		return []Vector2{{dir.Y, dir.X}}
+	case splitter1: // '-'
+		if dir.Y != 0 {
+			return []Vector2{{-1, 0}, {1, 0}}
+		}
+	case splitter2: // '|'
+		if dir.X != 0 {
+			return []Vector2{{0, -1}, {0, 1}}
+		}
 	}
-	return 0
+	return []Vector2{dir}
 }
 
-// check if a coordinate is in grid's bounds
-func isInGrid(grid map[Vector2]int, pos Vector2) bool {
-	_, ok := grid[pos]
-	return ok
-}
+// SendBeam follows a beam entering the contraption on the cell startPos going in direction startDir.
+// Every (position, direction) state is visited once, so the simulation ends exactly once
+// all the beams left the contraption or looped back on a known state.
+// It returns the energized cells sorted by row then column, and their count
+func SendBeam(ctx context.Context, input Input, startPos Vector2, startDir Vector2) ([]Vector2, int, error) {
+	visited := map[BeamHead]bool{}
+	energized := map[Vector2]bool{}
 
-func SendBeam(gridTypes map[Vector2]int, gridState map[Vector2]bool, startPos Vector2, startDir Vector2) int {
+	//each beam head is a beam entering the cell at its position
 	beamHeads := []BeamHead{{startDir, startPos}}
-	updateCell(&gridState, startPos)
-
-	cumul := 0
-	lastCount := -1
-	currentCount := 1
-
-	for cumul != 10 {
-		lastCount = currentCount
-		for i, b := range beamHeads {
-
-			nextPos := Vector2{
-				X: b.position.X + b.direction.X,
-				Y: b.position.Y + b.direction.Y,
+	for steps := 0; len(beamHeads) > 0; steps++ {
+		if steps%(1<<12) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, 0, err
 			}
+		}
+		b := beamHeads[len(beamHeads)-1]
+		beamHeads = beamHeads[:len(beamHeads)-1]
 
-			if isInGrid(gridTypes, nextPos) {
-				if gridTypes[nextPos] == empty {
-					updateBeamHead(&beamHeads[i], beamHeads[i].direction, nextPos)
-					currentCount += updateCell(&gridState, nextPos)
-				} else if gridTypes[nextPos] == mirror1 {
-					if b.direction.X == 1 {
-						updateBeamHead(&beamHeads[i], Vector2{0, -1}, nextPos)
-					} else if b.direction.X == -1 {
-						updateBeamHead(&beamHeads[i], Vector2{0, 1}, nextPos)
-					} else if b.direction.Y == 1 {
-						updateBeamHead(&beamHeads[i], Vector2{-1, 0}, nextPos)
-					} else if b.direction.Y == -1 {
-						updateBeamHead(&beamHeads[i], Vector2{1, 0}, nextPos)
-					}
-					currentCount += updateCell(&gridState, nextPos)
-				} else if gridTypes[nextPos] == mirror2 {
-					if b.direction.X == 1 {
-						updateBeamHead(&beamHeads[i], Vector2{0, 1}, nextPos)
-					} else if b.direction.X == -1 {
-						updateBeamHead(&beamHeads[i], Vector2{0, -1}, nextPos)
-					} else if b.direction.Y == 1 {
-						updateBeamHead(&beamHeads[i], Vector2{1, 0}, nextPos)
-					} else if b.direction.Y == -1 {
-						updateBeamHead(&beamHeads[i], Vector2{-1, 0}, nextPos)
-					}
-					currentCount += updateCell(&gridState, nextPos)
-				} else if gridTypes[nextPos] == splitter1 {
-					if b.direction.X != 0 {
-						updateBeamHead(&beamHeads[i], beamHeads[i].direction, nextPos)
-					} else {
-						updateBeamHead(&beamHeads[i], Vector2{-1, 0}, nextPos)
-						newBeamHead := BeamHead{Vector2{1, 0}, nextPos}
-						beamHeads = append(beamHeads, newBeamHead)
-					}
-					currentCount += updateCell(&gridState, nextPos)
-				} else if gridTypes[nextPos] == splitter2 {
-					if b.direction.Y != 0 {
-						updateBeamHead(&beamHeads[i], beamHeads[i].direction, nextPos)
-					} else {
-						updateBeamHead(&beamHeads[i], Vector2{0, -1}, nextPos)
-						newBeamHead := BeamHead{Vector2{0, 1}, nextPos}
-						beamHeads = append(beamHeads, newBeamHead)
-					}
-					currentCount += updateCell(&gridState, nextPos)
-				}
-			}
+		cell, inGrid := input.gridTypes[b.position]
+		if !inGrid || visited[b] {
+			continue
 		}
-		if lastCount == currentCount {
-			cumul++
-		} else {
-			cumul = 0
+		visited[b] = true
+		energized[b.position] = true
+
+		for _, dir := range deflect(cell, b.direction) {
+			next := Vector2{b.position.X + dir.X, b.position.Y + dir.Y}
+			beamHeads = append(beamHeads, BeamHead{dir, next})
 		}
 	}
-	return currentCount
+
+	cells := make([]Vector2, 0, len(energized))
+	for pos := range energized {
+		cells = append(cells, pos)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y != cells[j].Y {
+			return cells[i].Y < cells[j].Y
+		}
+		return cells[i].X < cells[j].X
+	})
+	return cells, len(cells), nil
 }
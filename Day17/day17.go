@@ -83,10 +83,13 @@ what is the least heat loss it can incur?
 package Day17
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
 	"math"
-	"os"
 	"strconv"
 )
 
@@ -104,31 +107,17 @@ type Grid struct {
 	costs map[Point]int
 }
 
-// called by main do display the result of both parts
-func Day17() [2]int {
-	return [2]int{
-		d17p1(),
-		d17p2(),
-	}
+func init() {
+	solver.Register(17, solver.New(Parse, Part1, Part2))
 }
 
-// get data from input file, Create a grid and fill it with Nodes
-func loadData(path string) Grid {
-	//open the file and check for error
-	file, err := os.Open(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	//on closing the file check for error
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+// Input is the city map with the heat loss of each block
+type Input = Grid
 
+// get data from input file, Create a grid and fill it with Nodes
+func Parse(input io.Reader) (Input, error) {
 	//walk through the input and construct the default nodes
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(input)
 	grid := Grid{Point{0, 0}, map[Point]int{}}
 	x, y := 0, 0
 	for scanner.Scan() {
@@ -136,7 +125,7 @@ func loadData(path string) Grid {
 		for _, r := range scanner.Text() {
 			v, err := strconv.Atoi(string(r))
 			if err != nil {
-				log.Fatal(err)
+				return Grid{}, solver.Errorf(y+1, x+1, "expected a heat loss digit, found %q", r)
 			} else {
 				grid.costs[Point{x, y}] = v
 			}
@@ -148,98 +137,132 @@ func loadData(path string) Grid {
 
 	//check if scanner encountered error during the scan
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return Grid{}, scanner.Err()
 	}
 
-	return grid
+	return grid, nil
+}
+
+// Rules describe how a crucible can move on the city map
+type Rules struct {
+	MinStraight int  // blocks to move in a straight line before turning or stopping
+	MaxStraight int  // blocks it can move in a straight line before it must turn
+	Turns       bool // it can turn 90 degrees left or right
+	Reverse     bool // it can go back the way it came
 }
 
+// Crucible is the crucible of part 1
+var Crucible = Rules{MinStraight: 1, MaxStraight: 3, Turns: true}
+
+// UltraCrucible is the crucible of part 2
+var UltraCrucible = Rules{MinStraight: 4, MaxStraight: 10, Turns: true}
+
 // part 1, find best path with constrain of max 3 steps
-func d17p1() int {
-	grid := loadData("./Day17/Ressources/day17_input.txt")
-	start := Node{Point{0, 0}, Point{0, 0}, 0}
-	goal := Node{Point{grid.size.x - 1, grid.size.y - 1}, Point{0, 0}, 0}
-	path, _ := AStar(start, goal, grid, 1, 3)
-	sum := 0
-	for _, p := range path {
-		sum += grid.costs[p.pos]
-	}
-	return sum - grid.costs[start.pos]
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	return leastHeatLoss(ctx, input, Crucible)
 }
 
 // part 2 find best path with steps between 4 and 10
-func d17p2() int {
-	grid := loadData("./Day17/Ressources/day17_input.txt")
-	start := Node{Point{0, 0}, Point{0, 0}, 0}
-	goal := Node{Point{grid.size.x - 1, grid.size.y - 1}, Point{0, 0}, 0}
-	path, _ := AStar(start, goal, grid, 4, 10)
-	sum := 0
-	for _, p := range path {
-		sum += grid.costs[p.pos]
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	return leastHeatLoss(ctx, input, UltraCrucible)
+}
+
+// heat loss of the best path from the top left block to the bottom right block
+func leastHeatLoss(ctx context.Context, grid Grid, rules Rules) (solver.Result, error) {
+	_, loss, err := ShortestPath(ctx, grid, Point{0, 0}, Point{grid.size.x - 1, grid.size.y - 1}, rules)
+	if err != nil {
+		return solver.Result{}, err
 	}
-	return sum - grid.costs[start.pos]
+	return solver.Int(loss), nil
 }
 
-func AStar(start, goal Node, grid Grid, minStep int, maxStep int) ([]Node, bool) {
+// ShortestPath finds the path with the least heat loss from start to goal with an A* search
+// over the (position, direction, steps in a straight line) states. The open states are kept
+// in a binary heap ordered by heat loss plus the manhattan distance to the goal times the
+// lowest heat loss of a block. The path starts with the start node, the heat loss of the
+// start block is not counted
+func ShortestPath(ctx context.Context, grid Grid, start, goal Point, rules Rules) ([]Node, int, error) {
+	if rules.MinStraight < 1 || rules.MaxStraight < rules.MinStraight {
+		return nil, 0, fmt.Errorf("invalid crucible rules, need 1 <= MinStraight <= MaxStraight, got %d and %d", rules.MinStraight, rules.MaxStraight)
+	}
+	if !isInGrid(start, grid) || !isInGrid(goal, grid) {
+		return nil, 0, fmt.Errorf("start %v or goal %v is out of the map", start, goal)
+	}
+
+	//the heuristic must never overestimate the remaining heat loss
+	minCost := math.MaxInt
+	for _, c := range grid.costs {
+		minCost = min(minCost, c)
+	}
+	heuristic := func(p Point) int {
+		return manhattanDistance(p, goal) * minCost
+	}
 
-	//intitialization
-	openSet := []Node{start}
+	first := Node{start, Point{0, 0}, 0}
+	loss := map[Node]int{first: 0}
 	cameFrom := map[Node]Node{}
-	gScore, fScore := map[Node]int{}, map[Node]int{}
-	gScore[start] = 0
-	fScore[start] = manhattanDistance(start.pos, goal.pos)
+	openSet := &nodeHeap{{first, heuristic(start)}}
 
-	//check all the open node until none are left
-	for len(openSet) != 0 {
+	for iteration := 0; openSet.Len() > 0; iteration++ {
+		if iteration%(1<<12) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, 0, err
+			}
+		}
 
-		//select you current node based on lwoest F score
-		i, current := lowestFScore(openSet, gScore)
-		if pointEqual(current.pos, goal.pos) {
-			return reconstructPath(cameFrom, current), true
+		item := heap.Pop(openSet).(heapItem)
+		current := item.node
+		currentLoss := loss[current]
+		//stale entry, the node was reached with a lower heat loss since it was pushed
+		if item.priority != currentLoss+heuristic(current.pos) {
+			continue
+		}
+		if current.pos == goal && current.steps >= rules.MinStraight {
+			return reconstructPath(cameFrom, current), currentLoss, nil
 		}
-		openSet = append(openSet[:i], openSet[i+1:]...)
 
-		//check all neighbours of the current node
 		for _, offset := range []Point{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
-			neighborV := Node{Point{current.pos.x + offset.x, current.pos.y + offset.y}, offset, 0}
-			invertOffset := Point{offset.x * -1, offset.y * -1}
-
-			//confirm that this neighbor is within constrains
-			validPos := isInGrid(neighborV.pos, grid)
-			aboveMaxStep := current.dir == offset && current.steps == maxStep
-			belowMinStep := current.dir != offset && current.steps < minStep && !pointEqual(current.pos, start.pos)
-
-			if !validPos || aboveMaxStep || belowMinStep {
+			next, ok := rules.move(current, offset)
+			if !ok || !isInGrid(next.pos, grid) {
 				continue
 			}
 
-			//update the step count taken based on direction from current to neighbor
-			if current.dir == offset {
-				neighborV.steps = current.steps + 1
-			} else if current.dir == invertOffset {
+			//keep the neighbor if it is reached with a lower heat loss than before
+			nextLoss := currentLoss + grid.costs[next.pos]
+			if known, seen := loss[next]; seen && known <= nextLoss {
 				continue
-			} else {
-				neighborV.steps = 1
-			}
-
-			//check if neighbor is a good match to be added to open list
-			tentativeGScore := gScore[current] + grid.costs[neighborV.pos]
-			_, initialized := gScore[neighborV]
-			if !initialized {
-				gScore[neighborV] = math.MaxInt
-			}
-			if tentativeGScore < gScore[neighborV] {
-				cameFrom[neighborV] = current
-				gScore[neighborV] = tentativeGScore
-				fScore[neighborV] = tentativeGScore + manhattanDistance(neighborV.pos, goal.pos)
-				if !contain(openSet, neighborV) {
-					openSet = append(openSet, neighborV)
-				}
 			}
+			loss[next] = nextLoss
+			cameFrom[next] = current
+			heap.Push(openSet, heapItem{next, nextLoss + heuristic(next.pos)})
 		}
 	}
 
-	return []Node{}, false
+	return nil, 0, fmt.Errorf("no path from %v to %v with these crucible rules", start, goal)
+}
+
+// state after a move of one block in direction offset, false if the rules forbid it
+func (r Rules) move(current Node, offset Point) (Node, bool) {
+	next := Node{Point{current.pos.x + offset.x, current.pos.y + offset.y}, offset, 1}
+
+	//the first move can go in any direction
+	if current.dir == (Point{0, 0}) {
+		return next, true
+	}
+
+	straight := offset == current.dir
+	reverse := offset == Point{-current.dir.x, -current.dir.y}
+	if straight {
+		next.steps = current.steps + 1
+		return next, next.steps <= r.MaxStraight
+	}
+	if current.steps < r.MinStraight {
+		return next, false
+	}
+	if reverse {
+		return next, r.Reverse
+	}
+	return next, r.Turns
 }
 
 func reconstructPath(cameFrom map[Node]Node, current Node) []Node {
@@ -259,27 +282,24 @@ func reconstructPath(cameFrom map[Node]Node, current Node) []Node {
 	return totalPath
 }
 
-func lowestFScore(slice []Node, gScore map[Node]int) (int, Node) {
-	min := math.MaxInt
-	minID := -1
-	var pos Node
-
-	for i, p := range slice {
-		s, ok := gScore[p]
-		if ok {
-			if s < min {
-				pos = p
-				min = s
-				minID = i
-			}
-		}
-	}
-
-	return minID, pos
+// heapItem is an open state with its heat loss plus the heuristic
+type heapItem struct {
+	node     Node
+	priority int
 }
 
-func pointEqual(a Point, b Point) bool {
-	return (a.x == b.x && a.y == b.y)
+// nodeHeap is a min heap of the open states on their priority
+type nodeHeap []heapItem
+
+func (h nodeHeap) Len() int           { return len(h) }
+func (h nodeHeap) Less(i, j int) bool { return h[i].priority < h[j].priority }
+func (h nodeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x any)        { *h = append(*h, x.(heapItem)) }
+func (h *nodeHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
 }
 
 // get the manhattan distance between 2 points of the grid
@@ -287,18 +307,6 @@ func manhattanDistance(p1, p2 Point) int {
 	return int(math.Abs(float64(p1.x-p2.x)) + math.Abs(float64(p1.y-p2.y)))
 }
 
-// check a Point item is in the specified slice
-func contain(slice []Node, item Node) bool {
-	for i := 0; i < len(slice); i++ {
-		if slice[i].dir.x == item.dir.x && slice[i].dir.y == item.dir.y &&
-			slice[i].pos.x == item.pos.x && slice[i].pos.y == item.pos.y &&
-			slice[i].steps == item.steps {
-			return true
-		}
-	}
-	return false
-}
-
 func isInGrid(pos Point, grid Grid) bool {
 	if pos.x < 0 || pos.y < 0 || pos.x > grid.size.x-1 || pos.y > grid.size.y-1 {
 
@@ -110,20 +110,17 @@ could the lagoon hold?
 package Day18
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
+	"context"
+	"io"
 	"math"
-	"os"
 	"strconv"
 	"strings"
 )
 
-func Day18() [2]int {
-	i := loadData()
-	return [2]int{
-		d18p1(i),
-		d18p2(i),
-	}
+func init() {
+	solver.Register(18, solver.New(Parse, Part1, Part2))
 }
 
 type Instruction struct {
@@ -137,36 +134,45 @@ type Point struct {
 	x, y int
 }
 
-func loadData() []Instruction {
-	file, err := os.Open("./Day18/Ressources/day18_input.txt")
-	if err != nil {
-		log.Fatal()
-	}
+// Input is the dig plan, one instruction per line
+type Input []Instruction
 
-	scanner := bufio.NewScanner(file)
-	instructions := []Instruction{}
+// Parse reads the dig plan, each instruction carries both the direct
+// direction/length (p1) and the one hidden in the color code (p2)
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
+	instructions := Input{}
 
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		split := strings.Split(scanner.Text(), " ")
+		if len(split) != 3 || !strings.HasPrefix(split[2], "(#") || !strings.HasSuffix(split[2], ")") || len(split[2]) != 9 {
+			return nil, solver.Errorf(lineNumber, 0, "expected direction, length and (#color) in line: %q", scanner.Text())
+		}
+		if split[0] != "U" && split[0] != "D" && split[0] != "L" && split[0] != "R" {
+			return nil, solver.Errorf(lineNumber, 1, "unknown direction %q", split[0])
+		}
 
 		//get strandard len (p1)
 		l, err := strconv.Atoi(split[1])
 		if err != nil {
-			log.Fatal(err)
+			return nil, solver.NewParseError(lineNumber, len(split[0])+2, err)
 		}
 
 		//convert hexColor hex to dir and len (p2)
+		colorColumn := len(split[0]) + len(split[1]) + 3
 		hexColor := strings.Split(strings.Split(split[2], "(")[1], ")")[0]
 		hexDirRaw := string([]rune(hexColor)[len(hexColor)-1])
 		hexDirInt, err := strconv.Atoi(hexDirRaw)
-		if err != nil {
-			log.Fatal(err)
+		if err != nil || hexDirInt > 3 {
+			return nil, solver.Errorf(lineNumber, colorColumn+7, "expected a direction digit between 0 and 3, found %q", hexDirRaw)
 		}
 		hexDir := intToStringDir(hexDirInt)
 		hexLenRaw := string([]rune(hexColor)[1 : len(hexColor)-1])
 		hexLenInt, err := strconv.ParseInt(hexLenRaw, 16, 64)
 		if err != nil {
-			log.Fatal(err)
+			return nil, solver.NewParseError(lineNumber, colorColumn+2, err)
 		}
 
 		//create the new instruction
@@ -181,17 +187,25 @@ func loadData() []Instruction {
 	}
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return nil, scanner.Err()
 	}
-	return instructions
+	return instructions, nil
 }
 
-func d18p1(instructions []Instruction) int {
-	return getAreaPickShoelace(instructions, false)
+func Part1(ctx context.Context, instructions Input) (solver.Result, error) {
+	area, err := getAreaPickShoelace(ctx, instructions, false)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(area), nil
 }
 
-func d18p2(instructions []Instruction) int {
-	return getAreaPickShoelace(instructions, true)
+func Part2(ctx context.Context, instructions Input) (solver.Result, error) {
+	area, err := getAreaPickShoelace(ctx, instructions, true)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(area), nil
 }
 
 func intToStringDir(i int) string {
@@ -206,11 +220,14 @@ func intToStringDir(i int) string {
 	}
 }
 
-func getAreaPickShoelace(input []Instruction, isHex bool) int {
+func getAreaPickShoelace(ctx context.Context, input []Instruction, isHex bool) (int, error) {
 	coordinates := make([]Point, len(input)+1)
 	x, y := 0, 0
 	sum := 0
 	for i, instruction := range input {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		coordinates[i] = Point{x, y}
 		var dir string
 		var len int
@@ -254,5 +271,5 @@ func getAreaPickShoelace(input []Instruction, isHex bool) int {
 	// we solve with => i = area - b/2 +1
 
 	total := area - float64(sum)/2 + 1 + float64(sum)
-	return int(total)
+	return int(total), nil
 }
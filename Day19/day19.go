@@ -110,32 +110,36 @@ package Day19
 
 import (
 	utils "AdventOfCode/Utils"
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
 
-func Day19() [2]int {
-	return [2]int{
-		d19p1(),
-		d19p2(),
-	}
+func init() {
+	solver.Register(19, solver.New(Parse, Part1, Part2))
 }
 
-func loadData() (map[string]Workflow, []map[string]int) {
-	file, err := os.Open("./Day19/Ressources/day19_input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	scanner := bufio.NewScanner(file)
+// Input is the system: the workflows by name and the ratings of each part
+type Input struct {
+	workflows map[string]Workflow
+	parts     []map[string]int
+}
+
+// Parse reads the workflows then, after the empty line, the parts ratings
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
 
 	workflows := map[string]Workflow{}
 	parts := []map[string]int{}
 
 	isWorkflow := true
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		if scanner.Text() == "" {
 			isWorkflow = false
 			continue
@@ -143,6 +147,9 @@ func loadData() (map[string]Workflow, []map[string]int) {
 
 		if isWorkflow {
 			split := strings.Split(scanner.Text(), "{")
+			if len(split) != 2 || !strings.HasSuffix(split[1], "}") {
+				return Input{}, solver.Errorf(lineNumber, 0, "expected name{rules} in line: %q", scanner.Text())
+			}
 			name := split[0]
 			split = strings.Split(split[1], "}")
 			rulesStrings := strings.Split(split[0], ",")
@@ -156,6 +163,9 @@ func loadData() (map[string]Workflow, []map[string]int) {
 				threshold := -1
 
 				if ok { //has a re-assignment condition
+					if len(chars) < 4 || (chars[1] != '<' && chars[1] != '>') {
+						return Input{}, solver.Errorf(lineNumber, solver.Column(scanner.Text(), r), "malformed rule %q", r)
+					}
 					rating = string(chars[0]) //set letter
 					if chars[1] == '>' {
 						comparator = Superior //set condition
@@ -164,7 +174,7 @@ func loadData() (map[string]Workflow, []map[string]int) {
 					tString := string(chars[2:index])
 					v, err := strconv.Atoi(tString)
 					if err != nil {
-						log.Fatal(err)
+						return Input{}, solver.NewParseError(lineNumber, solver.Column(scanner.Text(), r)+2, err)
 					}
 					threshold = v                           //set threshold
 					sendToAdress = strings.Split(r, ":")[1] //set send to
@@ -190,50 +200,47 @@ func loadData() (map[string]Workflow, []map[string]int) {
 			workflows[name] = newWorkflow
 
 		} else {
-			split := strings.Split(string([]rune(scanner.Text())[1:len(scanner.Text())-1]), ",")
-			xString := strings.Split(split[0], "=")[1]
-			mString := strings.Split(split[1], "=")[1]
-			aString := strings.Split(split[2], "=")[1]
-			sString := strings.Split(split[3], "=")[1]
-
-			xInt, err0 := strconv.Atoi(xString)
-			if err0 != nil {
-				log.Fatal(err0)
+			text := scanner.Text()
+			if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+				return Input{}, solver.Errorf(lineNumber, 0, "expected {x=..,m=..,a=..,s=..} in line: %q", text)
 			}
-
-			mInt, err1 := strconv.Atoi(mString)
-			if err1 != nil {
-				log.Fatal(err1)
+			split := strings.Split(text[1:len(text)-1], ",")
+			if len(split) != 4 {
+				return Input{}, solver.Errorf(lineNumber, 0, "expected 4 ratings in line: %q", text)
 			}
 
-			aInt, err2 := strconv.Atoi(aString)
-			if err2 != nil {
-				log.Fatal(err2)
-			}
-
-			sInt, err3 := strconv.Atoi(sString)
-			if err3 != nil {
-				log.Fatal(err3)
-			}
-
-			newPart := map[string]int{
-				"x": xInt,
-				"m": mInt,
-				"a": aInt,
-				"s": sInt,
+			newPart := map[string]int{}
+			column := 2
+			for i, name := range []string{"x", "m", "a", "s"} {
+				if !strings.HasPrefix(split[i], name+"=") {
+					return Input{}, solver.Errorf(lineNumber, column, "expected rating %s=.. found %q", name, split[i])
+				}
+				v, err := strconv.Atoi(split[i][2:])
+				if err != nil {
+					return Input{}, solver.NewParseError(lineNumber, column+2, err)
+				}
+				newPart[name] = v
+				column += len(split[i]) + 1
 			}
 			parts = append(parts, newPart)
 
 		}
 	}
 
-	return workflows, parts
+	if scanner.Err() != nil {
+		return Input{}, scanner.Err()
+	}
+
+	return Input{workflows: workflows, parts: parts}, nil
 }
 
-func d19p1() int {
-	workflows, parts := loadData()
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	workflows, parts := input.workflows, input.parts
 	validParts := []map[string]int{}
 	for _, p := range parts {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		result := processPart(workflows, "in", p)
 		if result {
 			validParts = append(validParts, p)
@@ -243,7 +250,7 @@ func d19p1() int {
 	for _, vp := range validParts {
 		sum += vp["x"] + vp["m"] + vp["a"] + vp["s"]
 	}
-	return sum
+	return solver.Int(sum), nil
 }
 
 type Workflow struct {
@@ -296,7 +303,124 @@ func processPart(workflows map[string]Workflow, wID string, part map[string]int)
 	return true
 }
 
-func d19p2() int {
+// part 2, count the distinct combinations of ratings from 1 to 4000 that are accepted
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	accepted, err := input.AcceptedRanges(ctx, AllParts())
+	if err != nil {
+		return solver.Result{}, err
+	}
+
+	sum := 0
+	for _, h := range accepted {
+		sum += h.Volume()
+	}
+	return solver.Int(sum), nil
+}
+
+// Range is an inclusive range of values of a rating
+type Range struct {
+	Min, Max int
+}
+
+// HyperRect is a box of parts, one range for each rating
+type HyperRect map[string]Range
 
-	return 0
+// Volume is the number of distinct parts in the box
+func (h HyperRect) Volume() int {
+	volume := 1
+	for _, r := range h {
+		if r.Max < r.Min {
+			return 0
+		}
+		volume *= r.Max - r.Min + 1
+	}
+	return volume
+}
+
+// copy the box with one of its ranges replaced
+func (h HyperRect) with(rating string, r Range) HyperRect {
+	out := make(HyperRect, len(h))
+	for k, v := range h {
+		out[k] = v
+	}
+	out[rating] = r
+	return out
+}
+
+// split a range on a rule, match is the part of the range that passes the rule
+// and rest the one that goes on to the next rule, either can be empty
+func (r Rule) split(in Range) (match, rest Range) {
+	if r.comparator == Superior {
+		return Range{max(in.Min, r.threshold+1), in.Max}, Range{in.Min, min(in.Max, r.threshold)}
+	}
+	return Range{in.Min, min(in.Max, r.threshold-1)}, Range{max(in.Min, r.threshold), in.Max}
+}
+
+// AllParts is the box of every part, each rating from 1 to 4000
+func AllParts() HyperRect {
+	all := HyperRect{}
+	for _, rating := range []string{"x", "m", "a", "s"} {
+		all[rating] = Range{1, 4000}
+	}
+	return all
+}
+
+// AcceptedRanges pushes a box of parts through the workflows starting at "in".
+// Each rule splits the box at its threshold, the matching side follows the rule
+// and the other side goes on with the next rule. The accepted boxes do not overlap
+func (input Input) AcceptedRanges(ctx context.Context, box HyperRect) ([]HyperRect, error) {
+	type state struct {
+		box HyperRect
+		wID string
+	}
+
+	accepted := []HyperRect{}
+	toVisit := []state{{box, "in"}}
+	for len(toVisit) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		current := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+
+		if current.wID == "A" {
+			accepted = append(accepted, current.box)
+			continue
+		}
+		if current.wID == "R" {
+			continue
+		}
+		workflow, ok := input.workflows[current.wID]
+		if !ok {
+			return nil, fmt.Errorf("unknown workflow %q", current.wID)
+		}
+
+		rest := current.box
+		for _, r := range workflow.rules {
+			if r.threshold == -1 {
+				toVisit = append(toVisit, state{rest, r.sendToAdress})
+				rest = nil
+				break
+			}
+			in, ok := rest[r.rating]
+			if !ok {
+				return nil, fmt.Errorf("unknown rating %q in workflow %q", r.rating, current.wID)
+			}
+			match, other := r.split(in)
+			if match.Min <= match.Max {
+				toVisit = append(toVisit, state{rest.with(r.rating, match), r.sendToAdress})
+			}
+			if other.Min > other.Max {
+				rest = nil
+				break
+			}
+			rest = rest.with(r.rating, other)
+		}
+
+		//like processPart, a box going past the last rule is accepted
+		if rest != nil {
+			accepted = append(accepted, rest)
+		}
+	}
+	return accepted, nil
 }
@@ -86,39 +86,33 @@ What is the sum of the power of these sets?
 package Day2
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
-func Day2() [2]int {
-	return [2]int{
-		d2p1(),
-		d2p2(),
-	}
+func init() {
+	solver.Register(2, solver.New(Parse, Part1, Part2))
 }
 
-func d2p1() int {
-	//step 1 open and scan the file
-	file, err := os.Open("./Day2/Ressources/day2_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
+// Game is one line of the record, rgbMax holds the highest count of
+// red, green and blue cubes revealed during the game
+type Game struct {
+	id     int
+	rgbMax [3]int
+}
 
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+// Input is the list of games from the record
+type Input []Game
 
-	scanner := bufio.NewScanner(file)
-
-	rgbInput := [3]int{12, 13, 14}
-	sum := 0
+// Parse reads every game of the record and keeps the max count of each color
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
+	games := Input{}
 
 	//define regex matching the different elements in the string
 	idRegex := regexp.MustCompile(`Game\s(\d+)`)
@@ -126,91 +120,73 @@ func d2p1() int {
 	greenRegex := regexp.MustCompile(`(\d+)\s+green`)
 	blueRegex := regexp.MustCompile(`(\d+)\s+blue`)
 
+	lineNumber := 0
 	for scanner.Scan() {
-		rgbMax := [3]int{0, 0, 0}
+		lineNumber++
+		game := Game{}
 
-		//get all patterns
-		idMatches := idRegex.FindAllString(scanner.Text(), -1)
-		redMatches := redRegex.FindAllString(scanner.Text(), -1)
-		greenMatches := greenRegex.FindAllString(scanner.Text(), -1)
-		blueMatches := blueRegex.FindAllString(scanner.Text(), -1)
+		//get game ID
+		idMatches := idRegex.FindStringSubmatch(scanner.Text())
+		if len(idMatches) != 2 {
+			return nil, solver.Errorf(lineNumber, 0, "no game ID found in line: %q", scanner.Text())
+		}
+		id, err := strconv.Atoi(idMatches[1])
+		if err != nil {
+			return nil, solver.NewParseError(lineNumber, solver.Column(scanner.Text(), idMatches[1]), err)
+		}
+		game.id = id
 
 		//get max value for each color in each games
-		rgbMax[0] = maxFromColorArray(redMatches)
-		rgbMax[1] = maxFromColorArray(greenMatches)
-		rgbMax[2] = maxFromColorArray(blueMatches)
-
-		//check if all input color count pass the test
-
-		redCheck := rgbMax[0] <= rgbInput[0] || rgbMax[0] == 0
-		greenCheck := rgbMax[1] <= rgbInput[1] || rgbMax[1] == 0
-		blueCheck := rgbMax[2] <= rgbInput[2] || rgbMax[2] == 0
+		game.rgbMax[0] = maxFromColorArray(redRegex.FindAllString(scanner.Text(), -1))
+		game.rgbMax[1] = maxFromColorArray(greenRegex.FindAllString(scanner.Text(), -1))
+		game.rgbMax[2] = maxFromColorArray(blueRegex.FindAllString(scanner.Text(), -1))
 
-		if redCheck && greenCheck && blueCheck {
-			//get game ID
-			if len(idMatches) > 0 {
-				s := strings.Split(idMatches[0], " ")
-				if len(s) == 2 {
-					if i, err := strconv.Atoi(s[1]); err == nil {
-						sum += i
-					}
-				}
-			}
-		}
+		games = append(games, game)
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	return sum
+	return games, nil
 }
 
-func d2p2() int {
-	//Step 1
-	file, err := os.Open("./Day2/Ressources/day2_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	rgbInput := [3]int{12, 13, 14}
+	sum := 0
 
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
+	for _, game := range input {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
 		}
-	}()
-
-	scanner := bufio.NewScanner(file)
+		rgbMax := game.rgbMax
 
-	sum := 0
+		//check if all input color count pass the test
 
-	//define regex matching the string elements
-	redRegex := regexp.MustCompile(`(\d+)\s+red`)
-	greenRegex := regexp.MustCompile(`(\d+)\s+green`)
-	blueRegex := regexp.MustCompile(`(\d+)\s+blue`)
+		redCheck := rgbMax[0] <= rgbInput[0] || rgbMax[0] == 0
+		greenCheck := rgbMax[1] <= rgbInput[1] || rgbMax[1] == 0
+		blueCheck := rgbMax[2] <= rgbInput[2] || rgbMax[2] == 0
 
-	for scanner.Scan() {
-		rgbMax := [3]int{1, 1, 1}
+		if redCheck && greenCheck && blueCheck {
+			sum += game.id
+		}
+	}
 
-		//get all patterns
-		redMatches := redRegex.FindAllString(scanner.Text(), -1)
-		greenMatches := greenRegex.FindAllString(scanner.Text(), -1)
-		blueMatches := blueRegex.FindAllString(scanner.Text(), -1)
+	return solver.Int(sum), nil
+}
 
-		//get max value for each color in each games
-		rgbMax[0] = maxFromColorArray(redMatches)
-		rgbMax[1] = maxFromColorArray(greenMatches)
-		rgbMax[2] = maxFromColorArray(blueMatches)
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	sum := 0
 
+	for _, game := range input {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		//increase sum with power
-		sum += rgbMax[0] * rgbMax[1] * rgbMax[2]
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		sum += game.rgbMax[0] * game.rgbMax[1] * game.rgbMax[2]
 	}
 
-	return sum
+	return solver.Int(sum), nil
 }
 
 // get the highest possible value found in the array
@@ -175,17 +175,19 @@ required to deliver a single low pulse to the module named rx?
 package Day20
 
 import (
+	utils "AdventOfCode/Utils"
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
 	"strings"
 )
 
-func Day20() [2]int {
-	return [2]int{
-		d20p1(),
-		d20p2(),
-	}
+func init() {
+	solver.Register(20, solver.New(Parse, Part1, Part2))
 }
 
 const (
@@ -202,33 +204,29 @@ type Module struct {
 	State  bool
 	Output []string
 	Memory map[string]string
-	Sent   string
 }
 
-var modules map[string]Module
-var lowCount int
-var highCount int
-
-/*
-var buttonPressCount int
-var flipflopList []string
-*/
+// Input is the module configuration by module name, with the memory of
+// every conjonction initialized to low pulses
+type Input map[string]Module
 
-func loadAndInit(path string) map[string]Module {
+// Parse reads the module configuration and init the conjonctions memory
+func Parse(input io.Reader) (Input, error) {
 
-	newModules := make(map[string]Module)
+	newModules := make(Input)
 
-	file, err := os.Open(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(input)
 	conjonctionList := []string{}
+	lineNumber := 0
 
 	for scanner.Scan() {
-		nameAndtype := strings.Split(scanner.Text(), " -> ")[0]
-		outputs := strings.Split(strings.Split(scanner.Text(), " -> ")[1], ", ")
+		lineNumber++
+		split := strings.Split(scanner.Text(), " -> ")
+		if len(split) != 2 || split[0] == "" {
+			return nil, solver.Errorf(lineNumber, 0, "expected name -> outputs in line: %q", scanner.Text())
+		}
+		nameAndtype := split[0]
+		outputs := strings.Split(split[1], ", ")
 		newModule := Module{}
 
 		if nameAndtype == BROADCASTER {
@@ -250,7 +248,7 @@ func loadAndInit(path string) map[string]Module {
 				newModules[name] = newModule
 				conjonctionList = append(conjonctionList, name)
 			} else {
-				log.Fatal("loadAndInit ERROR: module type not recognized from input:", typeKey)
+				return nil, solver.Errorf(lineNumber, 1, "module type %q not recognized", typeKey)
 			}
 		}
 	}
@@ -274,268 +272,240 @@ func loadAndInit(path string) map[string]Module {
 	}
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return nil, scanner.Err()
 	}
-	return newModules
+	return newModules, nil
 }
 
-func SendPulseToModule(input string, pulseType string, moduleName string) {
-	//update pulseCount
-	if pulseType == LOW_PULSE {
-		lowCount++
-	} else {
-		highCount++
-	}
+// Pulse is a pulse sent by a module to one of its outputs
+type Pulse struct {
+	From, To string
+	Type     string // LOW_PULSE or HIGH_PULSE
+}
 
-	//send pusle after behaviour check
+// Network is a running copy of a module configuration.
+// Every network owns its modules so that several networks can run side by side
+type Network struct {
+	modules Input
+}
 
-	module := modules[moduleName]
-	if module.Type == BROADCASTER { //module broadcaster
-		for _, next := range module.Output {
-			module.Sent = pulseType
-			SendPulseToModule(moduleName, pulseType, next)
+// NewNetwork builds a network in the initial state of the configuration,
+// the configuration itself is never modified
+func NewNetwork(input Input) *Network {
+	return &Network{modules: copyModules(input)}
+}
+
+// Press pushes the button and returns every pulse sent, the button pulse included,
+// in the order they were processed
+func (n *Network) Press() []Pulse {
+	return n.Send(Pulse{From: BUTTON, To: BROADCASTER, Type: LOW_PULSE})
+}
+
+// Send delivers a pulse and all the pulses it triggers. Pulses are processed
+// in the order they are sent, a module handles a pulse only once all the pulses
+// sent before it are handled
+func (n *Network) Send(first Pulse) []Pulse {
+	sent := []Pulse{first}
+	for i := 0; i < len(sent); i++ {
+		p := sent[i]
+		module, ok := n.modules[p.To]
+		if !ok {
+			//untyped module like rx, it only receives
+			continue
 		}
-	} else if module.Type == FLIPFLOP && pulseType == LOW_PULSE { //module flipflop with low pulse
-		if module.State {
-			module.State = false
-			modules[moduleName] = module
-			for _, next := range module.Output {
-				module.Sent = LOW_PULSE
-				SendPulseToModule(moduleName, LOW_PULSE, next)
-			}
-		} else {
-			module.State = true
-			modules[moduleName] = module
-			module.Sent = HIGH_PULSE
-			for _, next := range module.Output {
-				SendPulseToModule(moduleName, HIGH_PULSE, next)
+
+		out := ""
+		if module.Type == BROADCASTER {
+			out = p.Type
+		} else if module.Type == FLIPFLOP && p.Type == LOW_PULSE {
+			module.State = !module.State
+			out = LOW_PULSE
+			if module.State {
+				out = HIGH_PULSE
 			}
-		}
-	} else if module.Type == CONJONCTION { //module conjonction
-		module.Memory[input] = pulseType
-		allHigh := true
-		if pulseType == HIGH_PULSE {
-			for _, memoryPulseType := range module.Memory {
-				if memoryPulseType == LOW_PULSE {
-					allHigh = false
+		} else if module.Type == CONJONCTION {
+			//the memory map is shared with n.modules, no need to store the module back
+			module.Memory[p.From] = p.Type
+			out = LOW_PULSE
+			for _, m := range module.Memory {
+				if m == LOW_PULSE {
+					out = HIGH_PULSE
 					break
 				}
 			}
-		} else {
-			allHigh = false
 		}
-		for _, next := range module.Output {
-			if allHigh {
-				module.Sent = LOW_PULSE
-				SendPulseToModule(moduleName, LOW_PULSE, next)
-			} else {
-				module.Sent = HIGH_PULSE
-				SendPulseToModule(moduleName, HIGH_PULSE, next)
+		n.modules[p.To] = module
+
+		if out != "" {
+			for _, next := range module.Output {
+				sent = append(sent, Pulse{From: p.To, To: next, Type: out})
 			}
 		}
 	}
-	modules[moduleName] = module
+	return sent
 }
 
-/*
-func ReverseSearch(path string) []string {
-	file, err := os.Open(path)
-	if err != nil {
-		log.Fatal(err)
-	}
+// Snapshot is a saved state of a network
+type Snapshot struct {
+	modules Input
+}
 
-	scanner := bufio.NewScanner(file)
-	moduleMap := map[string][]string{}
-	typeMap := map[string]string{}
+// Snapshot saves the state of every flip-flop and conjonction memory
+func (n *Network) Snapshot() Snapshot {
+	return Snapshot{modules: copyModules(n.modules)}
+}
 
-	for scanner.Scan() {
-		nameAndtype := strings.Split(scanner.Text(), " -> ")[0]
-		outputs := strings.Split(strings.Split(scanner.Text(), " -> ")[1], ", ")
-		name := ""
-		mType := ""
+// Restore puts the network back in a saved state, the snapshot can be restored again later
+func (n *Network) Restore(s Snapshot) {
+	n.modules = copyModules(s.modules)
+}
 
-		if nameAndtype == BROADCASTER {
-			name = BROADCASTER
-			mType = BROADCASTER
-		} else {
-			name = string([]rune(nameAndtype)[1:])
-			mType = string([]rune(nameAndtype)[0])
-		}
-		typeMap[name] = mType
-		for i := 0; i < len(outputs); i++ {
-			contain, err := utils.SliceContains(moduleMap[outputs[i]], name)
-			if err != nil {
-				log.Fatal(err)
-			}
-			if !contain {
-				moduleMap[outputs[i]] = append(moduleMap[outputs[i]], name)
+// copy the modules with their own memory
+func copyModules(input Input) Input {
+	modules := make(Input, len(input))
+	for name, m := range input {
+		if m.Memory != nil {
+			memory := make(map[string]string, len(m.Memory))
+			for k, v := range m.Memory {
+				memory[k] = v
 			}
+			m.Memory = memory
 		}
+		modules[name] = m
 	}
+	return modules
+}
 
-	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
-	}
-
-	//reverse search
-	parents := []string{"rx"}
-	for i := 0; i < len(parents); i++ {
-		for _, pName := range moduleMap[parents[i]] {
-			contain, err := utils.SliceContains(parents, pName)
-			if err != nil {
-				log.Fatal(err)
-			}
-			if !contain {
-				parents = append(parents, pName)
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	network := NewNetwork(input)
+	lowCount, highCount := 0, 0
+	for i := 0; i < 1000; i++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
+		for _, p := range network.Press() {
+			if p.Type == LOW_PULSE {
+				lowCount++
+			} else {
+				highCount++
 			}
 		}
 	}
+	return solver.Int(lowCount * highCount), nil
+}
 
-	//keep only flipflops
-	ff := []string{}
-	for i := 0; i < len(parents); i++ {
-		if typeMap[parents[i]] == FLIPFLOP {
-			ff = append(ff, parents[i])
-		}
+// part 2, fewest button presses to send a single low pulse to rx.
+// rx is fed by one conjunction, it sends a low pulse once all its inputs sent it
+// a high pulse on the same press. Each output of the broadcaster drives an
+// independent counter ending on one of these inputs, the cycle of every counter
+// is measured alone and the cycles are combined with the CRT
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	feeder, err := findFeeder(input, "rx")
+	if err != nil {
+		return solver.Result{}, err
 	}
-	fmt.Println(ff)
-	return ff
-}*/
-
-func d20p1() int {
-	modules = loadAndInit("./Day20/Ressources/day20_input.txt")
-	lowCount = 0
-	highCount = 0
-	for i := 0; i < 1000; i++ {
-		SendPulseToModule("button", LOW_PULSE, BROADCASTER)
+
+	broadcaster, ok := input[BROADCASTER]
+	if !ok {
+		return solver.Result{}, errors.New("no broadcaster in the configuration")
 	}
-	return lowCount * highCount
-}
 
-func d20p2() int {
-	/*
-		lowCount, highCount = 0, 0
-		broadcastTargets := []string{"gn", "gb", "rb", "df"}
-		gnFF := []string{"nm", "gt", "tn", "pf", "gd", "gc", "ps", "rf", "gv", "gn", "pv", "pp"}
-		gbFF := []string{"fp", "rp", "gh", "td", "kz", "fz", "xm", "gb", "vx", "jb", "zq", "cs"}
-		rbFF := []string{"xz", "bg", "rq", "rb", "jl", "hh", "nz", "ms", "ts", "mt", "sx", "jn"}
-		dfFF := []string{"df", "gq", "vg", "fr", "cc", "ks", "lc", "lq", "ch", "qr", "dj", "nl"}
-
-		targetsFF := [][]string{
-			gnFF,
-			gbFF,
-			rbFF,
-			dfFF,
+	residues, periods := []int{}, []int{}
+	covered := map[string]bool{}
+	for _, start := range broadcaster.Output {
+		first, period, senders, err := measureCounter(ctx, input, start, feeder)
+		if err != nil {
+			return solver.Result{}, err
 		}
-
-		counts := make([]int, len(broadcastTargets))
-		for i := 0; i < len(broadcastTargets); i++ {
-			modules = loadAndInit("./Day20/Ressources/day20_input.txt")
-			buttonPressCount = 0
-			for {
-				buttonPressCount++
-				SendPulseToModule("button", LOW_PULSE, broadcastTargets[i])
-
-				if allOff(targetsFF[i]) {
-					counts[i] = buttonPressCount
-					break
-				}
+		for _, s := range senders {
+			if covered[s] {
+				return solver.Result{}, fmt.Errorf("input %q of %q is driven by more than one counter", s, feeder)
 			}
+			covered[s] = true
 		}
-		fmt.Println(counts)
-	*/
-	/*
-		ff := ReverseSearch("./Day20/Ressources/day20_input.txt")
-
-		print := false
-		count := 10000000
-
-		modules = loadAndInit("./Day20/Ressources/day20_input.txt")
-		fmt.Println("INIT")
-		checkState(ff, map[string]int{}, print)
-		fmt.Println()
-
-		modules = loadAndInit("./Day20/Ressources/day20_input.txt")
-		fmt.Println("ENTRY IS ", broadcastTargets[0])
-		affectedByEntry1 := map[string]int{}
-		for i := 0; i < count; i++ {
-			SendPulseToModule("button", LOW_PULSE, broadcastTargets[0])
-			checkState(ff, affectedByEntry1, print)
+		residues = append(residues, first)
+		periods = append(periods, period)
+	}
+	for name := range input[feeder].Memory {
+		if !covered[name] {
+			return solver.Result{}, fmt.Errorf("input %q of %q is not driven by any counter of the broadcaster", name, feeder)
 		}
-		printMemory(affectedByEntry1)
-		fmt.Println()
+	}
 
-		modules = loadAndInit("./Day20/Ressources/day20_input.txt")
-		fmt.Println("ENTRY IS ", broadcastTargets[1])
-		affectedByEntry2 := map[string]int{}
+	//the counter i sends its high pulse on presses first_i + k*period_i
+	x, lcm, err := utils.CRT(residues, periods)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	latest := 0
+	for _, first := range residues {
+		latest = max(latest, first)
+	}
+	if x < latest {
+		x += (latest - x + lcm - 1) / lcm * lcm
+	}
+	return solver.Int(x), nil
+}
 
-		for i := 0; i < count; i++ {
-			SendPulseToModule("button", LOW_PULSE, broadcastTargets[1])
-			checkState(ff, affectedByEntry2, print)
+// find the conjunction that is the only input of target
+func findFeeder(modules Input, target string) (string, error) {
+	feeders := []string{}
+	for name, m := range modules {
+		for _, o := range m.Output {
+			if o == target {
+				feeders = append(feeders, name)
+				break
+			}
 		}
-		printMemory(affectedByEntry2)
-		fmt.Println()
+	}
+	if len(feeders) != 1 {
+		return "", fmt.Errorf("expected a single module sending to %q, found %d", target, len(feeders))
+	}
+	if modules[feeders[0]].Type != CONJONCTION {
+		return "", fmt.Errorf("module %q sending to %q is not a conjonction", feeders[0], target)
+	}
+	return feeders[0], nil
+}
 
-		modules = loadAndInit("./Day20/Ressources/day20_input.txt")
-		fmt.Println("ENTRY IS ", broadcastTargets[2])
-		affectedByEntry3 := map[string]int{}
+// maximum number of presses to find the cycle of a counter
+const maxCounterPresses = 1 << 20
 
-		for i := 0; i < count; i++ {
-			SendPulseToModule("button", LOW_PULSE, broadcastTargets[2])
-			checkState(ff, affectedByEntry3, print)
-		}
-		printMemory(affectedByEntry3)
-		fmt.Println()
-
-		modules = loadAndInit("./Day20/Ressources/day20_input.txt")
-		fmt.Println("ENTRY IS ", broadcastTargets[3])
-		affectedByEntry4 := map[string]int{}
-		for i := 0; i < count; i++ {
-			SendPulseToModule("button", LOW_PULSE, broadcastTargets[3])
-			checkState(ff, affectedByEntry4, print)
-		}
-		printMemory(affectedByEntry4)
-		fmt.Println()
-	*/
-	return 0
-}
+// press the button with the broadcaster only talking to start, until the counter
+// behind start has sent 3 high pulses to feeder. Return the press of the first high
+// pulse, the period between the next ones and the modules that sent them
+func measureCounter(ctx context.Context, input Input, start, feeder string) (int, int, []string, error) {
+	network := NewNetwork(input)
+	hits := []int{}
+	senders := map[string]bool{}
 
-/*
-func checkState(names []string, memory map[string]int, print bool) {
-	for _, ffname := range names {
-		if modules[ffname].State {
-			if print {
-				fmt.Print(1, " ")
-			}
-			_, ok := memory[ffname]
-			if ok {
-				memory[ffname]++
-			} else {
-				memory[ffname] = 1
+	for press := 1; press <= maxCounterPresses && len(hits) < 3; press++ {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, nil, err
+		}
+		hit := false
+		for _, p := range network.Send(Pulse{From: BROADCASTER, To: start, Type: LOW_PULSE}) {
+			if p.To == feeder && p.Type == HIGH_PULSE {
+				hit = true
+				senders[p.From] = true
 			}
-
-		} else if print {
-			fmt.Print(0, " ")
+		}
+		if hit {
+			hits = append(hits, press)
 		}
 	}
-	if print {
-		fmt.Println()
-	}
-}
 
-func printMemory(memory map[string]int) {
-	for k, v := range memory {
-		fmt.Print(k, "=>", v, ", ")
+	if len(hits) < 3 {
+		return 0, 0, nil, fmt.Errorf("counter %q did not send 3 high pulses to %q in %d presses", start, feeder, maxCounterPresses)
+	}
+	period := hits[1] - hits[0]
+	if hits[2]-hits[1] != period {
+		return 0, 0, nil, fmt.Errorf("counter %q is not periodic, high pulses on presses %v", start, hits)
 	}
-	fmt.Println()
-}
 
-func allOff(ffs []string) bool {
-	for _, ffName := range ffs {
-		if modules[ffName].State {
-			return false
-		}
+	names := []string{}
+	for name := range senders {
+		names = append(names, name)
 	}
-	return true
+	sort.Strings(names)
+	return hits[0], period, names, nil
 }
-*/
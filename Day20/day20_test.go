@@ -0,0 +1,119 @@
+package Day20
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var examples = []struct {
+	name     string
+	input    string
+	expected string
+}{
+	{
+		name: "counter",
+		input: `broadcaster -> a, b, c
+%a -> b
+%b -> c
+%c -> inv
+&inv -> a`,
+		expected: "32000000",
+	},
+	{
+		name: "conjunctions",
+		input: `broadcaster -> a
+%a -> inv, con
+&inv -> b
+%b -> con
+&con -> output`,
+		expected: "11687500",
+	},
+}
+
+func parseExample(t *testing.T, input string) Input {
+	t.Helper()
+	modules, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return modules
+}
+
+func TestPart1(t *testing.T) {
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			result, err := Part1(context.Background(), parseExample(t, ex.input))
+			if err != nil {
+				t.Fatalf("Part1: %v", err)
+			}
+			if result.String() != ex.expected {
+				t.Errorf("Part1 = %s, expected %s", result, ex.expected)
+			}
+		})
+	}
+}
+
+// two networks built from one input are pressed in alternation, the second one
+// always lags one press behind and must send what the first one sent
+func TestNetworksSideBySide(t *testing.T) {
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			input := parseExample(t, ex.input)
+			initial := copyModules(input)
+			a, b := NewNetwork(input), NewNetwork(input)
+
+			history := [][]Pulse{a.Press()}
+			for i := 0; i < 20; i++ {
+				history = append(history, a.Press())
+				if got := b.Press(); !reflect.DeepEqual(got, history[i]) {
+					t.Fatalf("press %d of the second network sent %v, expected %v", i+1, got, history[i])
+				}
+			}
+
+			//the conjunction memories of each network and of the input are separate maps
+			for name, m := range a.modules {
+				if m.Type != CONJONCTION {
+					continue
+				}
+				if reflect.ValueOf(m.Memory).Pointer() == reflect.ValueOf(b.modules[name].Memory).Pointer() {
+					t.Errorf("conjunction %s shares its memory between the two networks", name)
+				}
+				if reflect.ValueOf(m.Memory).Pointer() == reflect.ValueOf(input[name].Memory).Pointer() {
+					t.Errorf("conjunction %s shares its memory with the input", name)
+				}
+			}
+			if !reflect.DeepEqual(input, initial) {
+				t.Errorf("pressing the networks modified the input")
+			}
+		})
+	}
+}
+
+func TestRestoreReplays(t *testing.T) {
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			network := NewNetwork(parseExample(t, ex.input))
+			for i := 0; i < 3; i++ {
+				network.Press()
+			}
+
+			snapshot := network.Snapshot()
+			expected := [][]Pulse{}
+			for i := 0; i < 10; i++ {
+				expected = append(expected, network.Press())
+			}
+
+			//a snapshot can be restored more than once
+			for round := 0; round < 2; round++ {
+				network.Restore(snapshot)
+				for i := range expected {
+					if got := network.Press(); !reflect.DeepEqual(got, expected[i]) {
+						t.Fatalf("round %d, press %d after Restore sent %v, expected %v", round, i+1, got, expected[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -169,40 +169,36 @@ Elf reach in exactly 26501365 steps?
 package Day21
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"math"
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 )
 
 type Point struct {
 	x, y int
 }
 
-type Float64Point struct {
-	x, y float64
+type Cell struct {
+	pos   Point
+	value rune
 }
 
-type Cell struct {
-	pos, chunkPos, parent Point
-	value                 rune
-	step                  int
-	visited               bool
+func init() {
+	solver.Register(21, solver.New(Parse, Part1, Part2))
 }
 
-func Day21() [2]int {
-	return [2]int{
-		d21p1(),
-		d21p2(),
-	}
+// Input is the garden map with the starting position and the map size
+type Input struct {
+	grid          map[Point]Cell
+	start, bounds Point
 }
 
-func ParseInput() (map[Point]Cell, Point, Point) {
-	file, err := os.Open("./Day21/Ressources/day21_input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	scanner := bufio.NewScanner(file)
+// Parse reads the garden map and locate the starting position S
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
 	grid := map[Point]Cell{}
 	start := Point{-1, -1}
 	bounds := Point{-1, -1}
@@ -211,19 +207,14 @@ func ParseInput() (map[Point]Cell, Point, Point) {
 		chars := []rune(scanner.Text())
 		for x := 0; x < len(chars); x++ {
 			pos := Point{x, y}
-
-			cell := Cell{
-				pos:      pos,
-				chunkPos: Point{0, 0},
-				parent:   Point{-1, -1},
-				value:    chars[x],
-				step:     0,
+			if chars[x] != '.' && chars[x] != '#' && chars[x] != 'S' {
+				return Input{}, solver.Errorf(y+1, x+1, "unknown cell %q", chars[x])
 			}
+
 			if chars[x] == 'S' {
 				start = pos
-				cell.visited = true
 			}
-			grid[pos] = cell
+			grid[pos] = Cell{pos: pos, value: chars[x]}
 		}
 
 		y++
@@ -231,22 +222,29 @@ func ParseInput() (map[Point]Cell, Point, Point) {
 		bounds.y = y
 	}
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return Input{}, scanner.Err()
+	}
+	if start.x == -1 {
+		return Input{}, errors.New("no starting position S found in the map")
 	}
-	file.Close()
-	return grid, start, bounds
+	return Input{grid: grid, start: start, bounds: bounds}, nil
 }
 
-func d21p1() int {
-	grid, start, _ := ParseInput()
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	grid, start := input.grid, input.start
 	maxStep, count := 64, 0
 	even := maxStep%2 == 0
 	if even {
 		count++
 	}
+	//step of every visited cell, kept apart from the grid so that the input is not modified
+	steps := map[Point]int{start: 0}
 	toVisit := []Point{start}
 	for i := 0; i < len(toVisit); i++ {
-		if grid[toVisit[i]].step > maxStep-1 {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
+		if steps[toVisit[i]] > maxStep-1 {
 			break
 		}
 		//find neighbours that are not visited and
@@ -271,172 +269,268 @@ func d21p1() int {
 
 		for _, nPos := range neighbors {
 			cell, ok := grid[nPos]
-			if ok && cell.value != '#' && !cell.visited {
-				cell.step = grid[toVisit[i]].step + 1
-				cell.visited = true
-				grid[nPos] = cell
+			_, visited := steps[nPos]
+			if ok && cell.value != '#' && !visited {
+				steps[nPos] = steps[toVisit[i]] + 1
 				toVisit = append(toVisit, nPos)
-				vEven := cell.step%2 == 0
+				vEven := steps[nPos]%2 == 0
 				if (even && vEven) || (!even && !vEven) {
 					count++
 				}
 			}
 		}
 	}
-	return count
+	return solver.Int(count), nil
 }
 
-func d21p2() int {
-	/*
-		searching for searchDelta(65,196,65+131*x) will give us the d2 when we only increase from 65 by 131*x wpaced by x = 0,1,2
-		with input known (puzzle input):
-			65+131*x = input
-			131*x = input - 65
-			x = (input-65)/131
-
-		delta of delta when jumping by 65 with periodicity of 131
-			=> d2 = (p2.y - p1.y) / (p2.x - p1.x) - (p1.y - p0.y) / (p1.x - p0.x)
-			=> n = d2/2
-
-		we will solve p2(input, ???)	where ??? is the answer to the puzzle
-		the dDelta result of the input will be:
-			=> n*x = d2
-
-		let's replace the original d2 equation with the new data for the prediction:
-			d2 is n*x so we can express:
-				(??? - p1.y) / (input - p1.x) - (p1.y - p0.y) / (p1.x - p0.x) = (n*x)
-				(??? - p1.y) / (input - p1.x) = (n*x) + (p1.y - p0.y) / (p1.x - p0.x)
-				(??? - p1.y) = ((n*x) + (p1.y - p0.y) / (p1.x - p0.x))*(input - p1.x)
-				??? = (((n*x) + (p1.y - p0.y) / (p1.x - p0.x))*(input - p1.x))+p1.y
-	*/
-	input := float64(26501365)
-	d2, p0, p1, _ := searchDelta(65, 65+131, 65+131*2)
-	n := d2 / 2
-	x := (input - 65) / 131
-	y := (((n * x) + (p1.y-p0.y)/(p1.x-p0.x)) * (input - p1.x)) + p1.y
-
-	return int(math.Ceil(y))
+// number of steps of part 2, given by the puzzle
+const part2Steps = 26501365
+
+// part 2, count the plots reached in exactly 26501365 steps on the infinite map.
+// The tiled counter is checked against a plain BFS for a few small step counts first
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	counter, err := NewTileCounter(ctx, input)
+	if err != nil {
+		return solver.Result{}, err
+	}
+
+	size := counter.size
+	for _, steps := range []int{size / 2, size, 2*size + size/2} {
+		want, err := bruteForceCount(ctx, input, steps)
+		if err != nil {
+			return solver.Result{}, err
+		}
+		if got := counter.Count(steps); got != want {
+			return solver.Result{}, fmt.Errorf("tiled count of %d steps is %d, brute force gives %d", steps, got, want)
+		}
+	}
+
+	return solver.Int(counter.Count(part2Steps)), nil
 }
 
-func searchDelta(a, b, c int) (float64, Float64Point, Float64Point, Float64Point) {
-	p0 := Float64Point{float64(a), float64(getCountInExpandingGrid(a))}
-	p1 := Float64Point{float64(b), float64(getCountInExpandingGrid(b))}
-	p2 := Float64Point{float64(c), float64(getCountInExpandingGrid(c))}
+// TileCounter counts the plots reachable on the infinite map for any number of steps.
+// It knows the shortest distance from S to every cell of the tiles around the start,
+// up to `radius` tiles away. Past this ring the distance to a cell grows by exactly one
+// map size for each tile, which gives the counts of the outer tiles in closed form
+type TileCounter struct {
+	size   int
+	radius int
+	//dist[tile][cell] of the tiles -radius..radius, -1 for a cell that cannot be reached
+	dist map[Point][]int
+}
 
-	d0 := (p1.y - p0.y) / (p1.x - p0.x)
-	d1 := (p2.y - p1.y) / (p2.x - p1.x)
+// largest ring tried before giving up on finding a periodic distance pattern
+const maxTileRadius = 16
 
-	d2 := d1 - d0
+// NewTileCounter measures the distances from S on the tiles around the start, the ring
+// of tiles used for the extrapolation grows until the distances are periodic from it
+func NewTileCounter(ctx context.Context, input Input) (TileCounter, error) {
+	if input.bounds.x != input.bounds.y {
+		return TileCounter{}, fmt.Errorf("the map must be square to be tiled, found %dx%d", input.bounds.x, input.bounds.y)
+	}
 
-	return d2, p0, p1, p2
+	for radius := 2; radius <= maxTileRadius; radius *= 2 {
+		//the BFS runs 2 tiles further than the ring so that the ring distances are exact
+		dist, err := tileDistances(ctx, input, radius+2)
+		if err != nil {
+			return TileCounter{}, err
+		}
+		counter := TileCounter{size: input.bounds.x, radius: radius, dist: dist}
+		if counter.periodic() {
+			return counter, nil
+		}
+	}
+	return TileCounter{}, fmt.Errorf("the distances are not periodic within %d tiles of the start", maxTileRadius)
 }
 
-func getCountInExpandingGrid(steps int) int {
-	templateGrid, start, bounds := ParseInput()
-	grid := deepMapCopy(templateGrid)
+// BFS from S on the tiles -radius..radius around the start map
+func tileDistances(ctx context.Context, input Input, radius int) (map[Point][]int, error) {
+	size := input.bounds.x
+	tiles := 2*radius + 1
+	width := tiles * size
 
-	maxStep, count := steps, 0
-	even := maxStep%2 == 0
-	if even {
-		count++
+	//distances on the whole block of tiles, the block origin is the top left of tile (-radius, -radius)
+	dist := make([]int, width*width)
+	for i := range dist {
+		dist[i] = -1
 	}
-	toVisit := []Point{start}
+	origin := Point{input.start.x + radius*size, input.start.y + radius*size}
+	dist[origin.y*width+origin.x] = 0
+	toVisit := []Point{origin}
 	for i := 0; i < len(toVisit); i++ {
-		if grid[toVisit[i]].step > maxStep-1 {
-			break
+		if i%(1<<16) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 		}
-		//find neighbours that are not visited and
-		neighbors := []Point{
-			{
-				x: toVisit[i].x,
-				y: toVisit[i].y - 1,
-			},
-			{
-				x: toVisit[i].x,
-				y: toVisit[i].y + 1,
-			},
-			{
-				x: toVisit[i].x - 1,
-				y: toVisit[i].y,
-			},
-			{
-				x: toVisit[i].x + 1,
-				y: toVisit[i].y,
-			},
+		current := toVisit[i]
+		d := dist[current.y*width+current.x]
+		for _, offset := range []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+			n := Point{current.x + offset.x, current.y + offset.y}
+			if n.x < 0 || n.y < 0 || n.x >= width || n.y >= width {
+				continue
+			}
+			if dist[n.y*width+n.x] != -1 || input.grid[Point{n.x % size, n.y % size}].value == '#' {
+				continue
+			}
+			dist[n.y*width+n.x] = d + 1
+			toVisit = append(toVisit, n)
 		}
+	}
 
-		for _, nPos := range neighbors {
-			cell, ok := grid[nPos]
+	//split the block per tile
+	perTile := map[Point][]int{}
+	for ty := -radius; ty <= radius; ty++ {
+		for tx := -radius; tx <= radius; tx++ {
+			cells := make([]int, size*size)
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					bx, by := (tx+radius)*size+x, (ty+radius)*size+y
+					cells[y*size+x] = dist[by*width+bx]
+				}
+			}
+			perTile[Point{tx, ty}] = cells
+		}
+	}
+	return perTile, nil
+}
 
-			if !ok {
-				direction := Point{
-					x: nPos.x - toVisit[i].x,
-					y: nPos.y - toVisit[i].y,
+// check that every tile of the ring is one map size further than the tile
+// next to it toward the start, cell by cell
+func (c TileCounter) periodic() bool {
+	r := c.radius
+	for ty := -r; ty <= r; ty++ {
+		for tx := -r; tx <= r; tx++ {
+			if abs(tx) != r && abs(ty) != r {
+				continue
+			}
+			//a corner tile is compared with both of its inner neighbors
+			inner := []Point{}
+			if abs(tx) == r {
+				inner = append(inner, Point{tx - sign(tx), ty})
+			}
+			if abs(ty) == r {
+				inner = append(inner, Point{tx, ty - sign(ty)})
+			}
+			for _, in := range inner {
+				outer, previous := c.dist[Point{tx, ty}], c.dist[in]
+				for i := range outer {
+					if (outer[i] == -1) != (previous[i] == -1) {
+						return false
+					}
+					if outer[i] != -1 && outer[i] != previous[i]+c.size {
+						return false
+					}
 				}
-				expandGrid(grid, templateGrid, direction, grid[toVisit[i]].chunkPos, bounds)
-				cell, ok = grid[nPos]
 			}
+		}
+	}
+	return true
+}
 
-			if ok && cell.value != '#' && !cell.visited {
-				cell.step = grid[toVisit[i]].step + 1
-				cell.visited = true
-				grid[nPos] = cell
-				toVisit = append(toVisit, nPos)
-				vEven := cell.step%2 == 0
-				if (even && vEven) || (!even && !vEven) {
+// Count returns the number of plots reachable in exactly steps steps
+func (c TileCounter) Count(steps int) int {
+	r := c.radius
+	count := 0
+	for tile, cells := range c.dist {
+		if abs(tile.x) > r || abs(tile.y) > r {
+			continue
+		}
+		onRing := abs(tile.x) == r || abs(tile.y) == r
+		corner := abs(tile.x) == r && abs(tile.y) == r
+		for _, d := range cells {
+			if d == -1 || d > steps {
+				continue
+			}
+			if !onRing {
+				if d%2 == steps%2 {
 					count++
 				}
+				continue
 			}
+			//the ring tile and the tiles behind it, n tiles further away is d + n*size.
+			//Behind a corner there are n+1 tiles n tiles further away
+			count += c.outerCount(d, steps, corner)
 		}
 	}
 	return count
 }
 
-func expandGrid(gridTobeExpanded, template map[Point]Cell, direction, origin, bounds Point) {
-	left := Point{-1, 0}
-	right := Point{1, 0}
-	up := Point{0, -1}
-	down := Point{0, 1}
-
-	offsetX, offsetY := 0, 0
-
-	if direction == left {
-		offsetX = -bounds.x
-	} else if direction == right {
-		offsetX = bounds.x
-	} else if direction == up {
-		offsetY = -bounds.y
-	} else if direction == down {
-		offsetY = bounds.y
+// count the copies of a cell at distance d + n*size, n >= 0, that are reached
+// in exactly steps steps. Each copy counts n+1 times behind a corner
+func (c TileCounter) outerCount(d, steps int, corner bool) int {
+	last := (steps - d) / c.size
+
+	//the parity of d + n*size must be the one of steps
+	first, stride := 0, 1
+	if c.size%2 == 0 {
+		if d%2 != steps%2 {
+			return 0
+		}
 	} else {
-		log.Fatal("[expandGrid] wrong direction:", direction)
+		first, stride = (steps-d)%2, 2
+	}
+	if first > last {
+		return 0
+	}
+
+	k := (last-first)/stride + 1
+	if !corner {
+		return k
+	}
+	//sum of n+1 for n = first, first+stride, ..., first+(k-1)*stride
+	return k*(first+1) + stride*k*(k-1)/2
+}
+
+// count the plots reached in exactly steps steps with a BFS on the infinite map
+func bruteForceCount(ctx context.Context, input Input, steps int) (int, error) {
+	size := input.bounds
+	wrap := func(v, n int) int {
+		return ((v % n) + n) % n
 	}
 
-	for y := 0; y < bounds.y; y++ {
-		for x := 0; x < bounds.x; x++ {
-			pos := Point{x: origin.x + x + offsetX, y: origin.y + y + offsetY}
-			newCell := Cell{
-				pos:      pos,
-				chunkPos: Point{x: origin.x + offsetX, y: origin.y + offsetY},
-				value:    template[Point{x, y}].value,
+	dist := map[Point]int{input.start: 0}
+	toVisit := []Point{input.start}
+	count := 0
+	for i := 0; i < len(toVisit); i++ {
+		if i%(1<<16) == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+		current := toVisit[i]
+		d := dist[current]
+		if d%2 == steps%2 {
+			count++
+		}
+		if d == steps {
+			continue
+		}
+		for _, offset := range []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+			n := Point{current.x + offset.x, current.y + offset.y}
+			if _, seen := dist[n]; seen {
+				continue
 			}
-			gridTobeExpanded[pos] = newCell
+			if input.grid[Point{wrap(n.x, size.x), wrap(n.y, size.y)}].value == '#' {
+				continue
+			}
+			dist[n] = d + 1
+			toVisit = append(toVisit, n)
 		}
 	}
+	return count, nil
 }
 
-func deepMapCopy(grid map[Point]Cell) map[Point]Cell {
-	newGrid := map[Point]Cell{}
-	for k, v := range grid {
-		newCell := Cell{
-			pos:      Point{v.pos.x, v.pos.y},
-			chunkPos: Point{v.chunkPos.x, v.chunkPos.y},
-			parent:   Point{v.parent.x, v.parent.y},
-			value:    v.value,
-			step:     v.step,
-			visited:  v.visited,
-		}
-		newGrid[k] = newCell
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	if v < 0 {
+		return -1
 	}
-	return newGrid
+	return 1
 }
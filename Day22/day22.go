@@ -182,9 +182,10 @@ package Day22
 
 import (
 	utils "AdventOfCode/Utils"
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -201,29 +202,37 @@ type Brick struct {
 	isSupporting []int
 }
 
-func Day22() [2]int {
-	return [2]int{
-		d22p1(),
-		d22p2(),
-	}
+func init() {
+	solver.Register(22, solver.New(Parse, Part1, Part2))
 }
 
-func loadData() (map[int]Brick, map[Point3]int, Point3) {
-	file, err := os.Open("./Day22/Ressources/day22_input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer file.Close()
+// Input is the snapshot of the falling bricks: the bricks by ID, the grid
+// holding the brick ID of each occupied position and the max coordinates
+type Input struct {
+	bricks map[int]Brick
+	grid   map[Point3]int
+	bounds Point3
+}
+
+// Parse reads the snapshot and place every brick in the grid
+func Parse(input io.Reader) (Input, error) {
 
 	bricks := map[int]Brick{}
 	brickID := 1
 	bounds := Point3{-1, -1, -1}
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(input)
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		newBrick := Brick{}
 
 		//Parse start and end positions
 		pos2 := strings.Split(scanner.Text(), "~")
+		if len(pos2) != 2 || strings.Count(pos2[0], ",") != 2 || strings.Count(pos2[1], ",") != 2 {
+			return Input{}, solver.Errorf(lineNumber, 0, "expected x,y,z~x,y,z in line: %q", scanner.Text())
+		}
+		//column of the current coordinate, start and end are separated by '~'
+		column := 1
 		startEnd := [][]string{
 			strings.Split(pos2[0], ","),
 			strings.Split(pos2[1], ","),
@@ -232,8 +241,9 @@ func loadData() (map[int]Brick, map[Point3]int, Point3) {
 			for j, coord := range pos {
 				n, err := strconv.Atoi(coord)
 				if err != nil {
-					log.Fatal(err)
+					return Input{}, solver.NewParseError(lineNumber, column, err)
 				}
+				column += len(coord) + 1
 				if i == 0 {
 					if j == 0 {
 						newBrick.start.x = n
@@ -337,14 +347,35 @@ func loadData() (map[int]Brick, map[Point3]int, Point3) {
 	}
 
 	if scanner.Err() != nil {
-		log.Fatal(scanner.Err())
+		return Input{}, scanner.Err()
+	}
+	return Input{bricks: bricks, grid: grid, bounds: bounds}, nil
+}
+
+// copy the bricks and the grid so that a part can drop the bricks
+// without changing the parsed input
+func (input Input) clone() Input {
+	bricks := make(map[int]Brick, len(input.bricks))
+	for id, b := range input.bricks {
+		b.allPos = append([]Point3{}, b.allPos...)
+		b.base = append([]Point3{}, b.base...)
+		b.supportedBy = append([]int{}, b.supportedBy...)
+		b.isSupporting = append([]int{}, b.isSupporting...)
+		bricks[id] = b
 	}
-	return bricks, grid, bounds
+	grid := make(map[Point3]int, len(input.grid))
+	for pos, id := range input.grid {
+		grid[pos] = id
+	}
+	return Input{bricks: bricks, grid: grid, bounds: input.bounds}
 }
 
-func applyGravity(bricks map[int]Brick, grid map[Point3]int) {
+func applyGravity(ctx context.Context, bricks map[int]Brick, grid map[Point3]int) error {
 	//apply gravity to the bricks
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		hasMovedThisTurn := false
 		for key, brick := range bricks {
 			//check if the brick can drop by looking down from all "base" pos
@@ -381,7 +412,7 @@ func applyGravity(bricks map[int]Brick, grid map[Point3]int) {
 					newAllPos = append(newAllPos, nextPos)
 					isBase, err := utils.SliceContains(brick.base, pos)
 					if err != nil {
-						log.Fatal(err)
+						return err
 					}
 					if isBase {
 						newBases = append(newBases, nextPos)
@@ -419,18 +450,22 @@ func applyGravity(bricks map[int]Brick, grid map[Point3]int) {
 					supportBrick.isSupporting = append(supportBrick.isSupporting, key)
 					bricks[grid[nextPos]] = supportBrick
 				} else if err != nil {
-					log.Fatal(err)
+					return err
 				}
 			}
 			bricks[key] = brick
 
 		}
 	}
+	return nil
 }
 
-func d22p1() int {
-	bricks, grid, _ := loadData()
-	applyGravity(bricks, grid)
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	settled := input.clone()
+	bricks, grid := settled.bricks, settled.grid
+	if err := applyGravity(ctx, bricks, grid); err != nil {
+		return solver.Result{}, err
+	}
 
 	//find how many can be safely disintegrated
 	//a brick can be disintegrated if all the suported brick have at least another supporter
@@ -447,17 +482,23 @@ func d22p1() int {
 			toDestroy = append(toDestroy, key)
 		}
 	}
-	return len(toDestroy)
+	return solver.Int(len(toDestroy)), nil
 }
 
-func d22p2() int {
-	bricks, grid, _ := loadData()
-	applyGravity(bricks, grid)
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	settled := input.clone()
+	bricks, grid := settled.bricks, settled.grid
+	if err := applyGravity(ctx, bricks, grid); err != nil {
+		return solver.Result{}, err
+	}
 
 	sum := 0
 
 	//we will look at each bricks to see what hapens if we destroy them
 	for id0, _ := range bricks {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		areGoingTofall := []int{id0}
 		//we will repeat the process below for each brick known as going to fall
 		for i := 0; i < len(areGoingTofall); i++ {
@@ -469,7 +510,7 @@ func d22p2() int {
 				//otherwise it mean they are supported by a stable brick
 				for _, id2 := range bricks[id1].supportedBy {
 					if ok, err := utils.SliceContains(areGoingTofall, id2); err != nil {
-						log.Fatal(err)
+						return solver.Result{}, err
 
 					} else if !ok {
 						willFall = false
@@ -479,7 +520,7 @@ func d22p2() int {
 				//once confirmed we can add this brick to the list so that we continue to check going up
 				if willFall {
 					if ok, err := utils.SliceContains(areGoingTofall, id1); err != nil {
-						log.Fatal(err)
+						return solver.Result{}, err
 					} else if !ok {
 						areGoingTofall = append(areGoingTofall, id1)
 					}
@@ -494,7 +535,7 @@ func d22p2() int {
 		}
 	}
 
-	return sum
+	return solver.Int(sum), nil
 }
 
 //BELOW FUNCTION CAN BE USED TO VISUALIZE THE GRID IN A SIMILAR WAY AS THE EXAMPLES
@@ -117,11 +117,13 @@ Find the longest hike you can take through the surprisingly dry hiking trails li
 package Day23
 
 import (
-	utils "AdventOfCode/Utils"
+	"AdventOfCode/Utils/solver"
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"math"
-	"os"
+	"io"
+	"strings"
 )
 
 type Point2 struct {
@@ -129,54 +131,50 @@ type Point2 struct {
 }
 
 type Cell struct {
-	value   string
-	step    int
-	visited bool
-	dir     Point2
-	parent  Point2
+	value string
 }
 
+// Grid holds the open cells of the map, paths and slopes
 type Grid struct {
-	smallGrid, fullGrid map[Point2]Cell
-	start, end, bounds  Point2
-	links               map[Point2][]Point2
+	smallGrid          map[Point2]Cell
+	start, end, bounds Point2
 }
 
-func Day23() [2]int {
-	return [2]int{
-		d23p1(),
-		d23p2(),
-	}
+func init() {
+	solver.Register(23, solver.New(Parse, Part1, Part2))
 }
 
-func d23p1() int {
-	return FindLongestPathlenghtP1(DfsP1(loadData("./Day23/Ressources/day23_input.txt")))
+// part 1, longest hike when the slopes can only be walked downhill
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	return longestHike(ctx, input, true)
 }
 
-func d23p2() int {
-	gridData := loadData("./Day23/Ressources/day23_input.txt")
-	gridData.links = preComputeLinks(gridData)
-
-	//40 millions paths or so (so could be improved)
-	size := brutForceP2(gridData)
-	return size
+// part 2, longest hike when the slopes are plain paths
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	return longestHike(ctx, input, false)
 }
 
-func loadData(path string) Grid {
-	file, errFile := os.Open(path)
-	if errFile != nil {
-		panic(errFile)
+func longestHike(ctx context.Context, input Input, slopes bool) (solver.Result, error) {
+	graph, err := NewJunctionGraph(input)
+	if err != nil {
+		return solver.Result{}, err
 	}
-	defer func() {
-		if errClose := file.Close(); errClose != nil {
-			panic(errClose)
-		}
-	}()
-	scanner := bufio.NewScanner(file)
+	size, err := graph.LongestPath(ctx, slopes)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(size), nil
+}
+
+// Input is the map of the hiking trails
+type Input = Grid
+
+// Parse reads the map of the trails and find the start and end positions
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
 
 	gridData := Grid{
 		smallGrid: map[Point2]Cell{},
-		fullGrid:  map[Point2]Cell{},
 		start:     Point2{-1, -1},
 		end:       Point2{-1, -1},
 		bounds:    Point2{0, 0},
@@ -190,17 +188,11 @@ func loadData(path string) Grid {
 		//Create cells for each characters
 		for x := 0; x < len(text); x++ {
 			pos := Point2{x, gridData.bounds.y}
-			cell := Cell{
-				value:  string(text[x]),
-				step:   0,
-				dir:    Point2{0, 0},
-				parent: Point2{-1, -1},
+			if !strings.ContainsRune("#.<>^v", text[x]) {
+				return Grid{}, solver.Errorf(gridData.bounds.y+1, x+1, "unknown tile %q", text[x])
 			}
-			if cell.value == "#" {
-				gridData.fullGrid[pos] = cell
-			} else {
-				gridData.smallGrid[pos] = cell
-				gridData.fullGrid[pos] = cell
+			if text[x] != '#' {
+				gridData.smallGrid[pos] = Cell{value: string(text[x])}
 			}
 		}
 		gridData.bounds.y++
@@ -211,9 +203,20 @@ func loadData(path string) Grid {
 		}
 	}
 
+	if scanner.Err() != nil {
+		return Grid{}, scanner.Err()
+	}
+
+	if len(lines) == 0 {
+		return Grid{}, errors.New("empty map")
+	}
+
 	//find start and end pos in first and last line
 	foundStart, foundEnd := false, false
 	for i := 0; i < gridData.bounds.x; i++ {
+		if i >= len(lines[0]) || i >= len(lines[len(lines)-1]) {
+			break
+		}
 		if lines[0][i] == '.' {
 			gridData.start = Point2{i, 0}
 			foundStart = true
@@ -227,237 +230,193 @@ func loadData(path string) Grid {
 		}
 	}
 
-	if scanner.Err() != nil {
-		panic(scanner.Err())
+	if !foundStart || !foundEnd {
+		return Grid{}, errors.New("no start or end position found in the first and last lines")
 	}
-	return gridData
+	return gridData, nil
 }
 
-func DfsP1(gridData Grid) Grid {
-	//init start cell
-	startCell := gridData.smallGrid[gridData.start]
-	startCell.step = 1
-	startCell.dir = Point2{0, 1}
-	gridData.smallGrid[gridData.start] = startCell
-
-	toVisit := []Point2{gridData.start}
-	for i := 0; i < len(toVisit); i++ {
-		currentPos := toVisit[i]
-		currentCell := gridData.smallGrid[currentPos]
-		currentCell.visited = true
-
-		for _, dir := range []Point2{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
-			nextPos := Point2{currentPos.x + dir.x, currentPos.y + dir.y}
-			nextCell, exist := gridData.smallGrid[nextPos]
-
-			//ignore out of bounds positions
-			if !exist {
-				continue
-			}
-
-			//prevent from backtracking
-			if dir.x == currentCell.dir.x*-1 && dir.y == currentCell.dir.y*-1 {
-				continue
-			}
+// Edge is a corridor between the junctions a and b, length is its number of steps.
+// forward tells if it can be walked from a to b without going up a slope,
+// backward if it can be walked from b to a
+type Edge struct {
+	a, b              int
+	length            int
+	forward, backward bool
+}
 
-			//prevent from climbing slopes
-			if (dir.x == -1 && nextCell.value == ">") || (dir.y == -1 && nextCell.value == "v") {
-				continue
-			}
+// JunctionGraph is the trail map contracted to its junctions: the start, the end
+// and every cell with more than 2 paths around it. The corridors between them
+// become weighted edges that keep the direction the slopes allow, so the same
+// graph serves the hikes with and without slopes
+type JunctionGraph struct {
+	nodes      []Point2
+	edges      []Edge
+	adjacent   [][]int // indexes in edges of the corridors of each junction
+	start, end int
+}
 
-			//update the neighbor cell and add to the toVisit stack
-			if (nextCell.visited && nextCell.step < currentCell.step+1) || !nextCell.visited {
-				nextCell.step = currentCell.step + 1
-				nextCell.dir = dir
-				nextCell.parent = currentPos
-				gridData.smallGrid[nextPos] = nextCell
-				toVisit = append(toVisit, nextPos)
-			}
-		}
+// the visited junctions of a hike are kept in the bits of an uint64
+const maxJunctions = 64
 
-		gridData.smallGrid[currentPos] = currentCell
-		gridData.fullGrid[currentPos] = currentCell
-	}
-	return gridData
-}
+var directions = []Point2{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
 
-func FindLongestPathlenghtP1(gridData Grid) int {
-	path := []Point2{gridData.end}
-	for i := 0; i < len(path); i++ {
-		pos := path[i]
+// direction to walk down each slope
+var slopeDirections = map[string]Point2{"<": {-1, 0}, ">": {1, 0}, "^": {0, -1}, "v": {0, 1}}
 
-		if pos.x == gridData.start.x && pos.y == gridData.start.y {
-			break
-		}
+// NewJunctionGraph contracts the map, every corridor records in which
+// directions it can be walked downhill
+func NewJunctionGraph(gridData Grid) (JunctionGraph, error) {
+	graph := JunctionGraph{}
+	index := map[Point2]int{}
+	addNode := func(pos Point2) {
+		index[pos] = len(graph.nodes)
+		graph.nodes = append(graph.nodes, pos)
+	}
 
-		maxStep := 0
-		maxPos := Point2{-1, -1}
-		for _, dir := range []Point2{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
-			nextPos := Point2{pos.x + dir.x, pos.y + dir.y}
-			nextCell, exist := gridData.smallGrid[nextPos]
-			inPath, err := utils.SliceContains(path, nextPos)
-			canRight := dir.x != 1 || (dir.x == 1 && nextCell.value != ">")
-			canDown := dir.y != 1 || (dir.y == 1 && nextCell.value != "v")
-
-			if err != nil {
-				panic(err)
+	//start and end first then the junctions in reading order so that the graph does not depend on the map order
+	addNode(gridData.start)
+	addNode(gridData.end)
+	for y := 0; y < gridData.bounds.y; y++ {
+		for x := 0; x < gridData.bounds.x; x++ {
+			pos := Point2{x, y}
+			if _, open := gridData.smallGrid[pos]; !open || pos == gridData.start || pos == gridData.end {
+				continue
 			}
-
-			if exist && !inPath && canRight && canDown {
-				if nextCell.step >= maxStep {
-					maxStep = nextCell.step
-					maxPos = nextPos
-				}
+			if len(openNeighbors(gridData, pos)) > 2 {
+				addNode(pos)
 			}
 		}
-		if maxPos.x != -1 && maxPos.y != -1 {
-			path = append(path, maxPos)
-		}
 	}
-	return len(path) - 1
-}
-
-func preComputeLinks(gridData Grid) map[Point2][]Point2 {
-	splitCount := 0
-	links := map[Point2][]Point2{}
-
-	for pos, _ := range gridData.smallGrid {
-		for _, dir := range []Point2{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
-			nextPos := Point2{pos.x + dir.x, pos.y + dir.y}
-			if _, exist := gridData.smallGrid[nextPos]; exist {
-				links[pos] = append(links[pos], dir)
+	if len(graph.nodes) > maxJunctions {
+		return JunctionGraph{}, fmt.Errorf("the map has %d junctions, at most %d are supported", len(graph.nodes), maxJunctions)
+	}
+	graph.start, graph.end = index[gridData.start], index[gridData.end]
+
+	//walk every corridor leaving each junction until the next junction,
+	//a corridor is walked from both of its ends and kept from its lowest junction
+	graph.adjacent = make([][]int, len(graph.nodes))
+	for from, pos := range graph.nodes {
+		for _, first := range openNeighbors(gridData, pos) {
+			previous, current := pos, first
+			edge := Edge{a: from, length: 1, forward: true, backward: true}
+			deadEnd := false
+			for {
+				edge.forward = edge.forward && canStep(gridData, previous, current)
+				edge.backward = edge.backward && canStep(gridData, current, previous)
+				if _, isNode := index[current]; isNode {
+					break
+				}
+				next := Point2{-1, -1}
+				for _, n := range openNeighbors(gridData, current) {
+					if n != previous {
+						next = n
+						break
+					}
+				}
+				if next.x == -1 {
+					deadEnd = true
+					break
+				}
+				previous, current = current, next
+				edge.length++
 			}
-		}
-		if len(links[pos]) > 2 {
-			splitCount++
+			edge.b = index[current]
+			if deadEnd || edge.b <= from {
+				continue
+			}
+			graph.adjacent[edge.a] = append(graph.adjacent[edge.a], len(graph.edges))
+			graph.adjacent[edge.b] = append(graph.adjacent[edge.b], len(graph.edges))
+			graph.edges = append(graph.edges, edge)
 		}
 	}
-	return links
+	return graph, nil
 }
 
-type Path struct {
-	done    bool
-	head    Point2
-	history map[Point2]struct{}
+// other end of an edge and if the edge can be walked from node, slopes tells
+// if the slopes can only be walked downhill
+func (e Edge) from(node int, slopes bool) (int, bool) {
+	if node == e.a {
+		return e.b, !slopes || e.forward
+	}
+	return e.a, !slopes || e.backward
 }
 
-func brutForceP2(gridData Grid) int {
-	paths := []Path{}
-	maxFound := 0
-
-	paths = append(paths, Path{
-		done:    false,
-		head:    gridData.start,
-		history: map[Point2]struct{}{gridData.start: {}},
-	})
-	explored := 0
-
-	for i := 0; i < len(paths); i++ {
-		maxFound = int(math.Max(float64(explorePath(gridData, paths[i], &paths)), float64(maxFound)))
-
-		//clean completed paths
-		paths[i] = paths[len(paths)-1]
-		paths = paths[:len(paths)-1]
-		i--
-
-		//print current status
-		explored++
-		if explored%10000 == 0 {
-			fmt.Println("explored:", explored, "currentMax:", maxFound)
+// open cells around a position
+func openNeighbors(gridData Grid, pos Point2) []Point2 {
+	neighbors := []Point2{}
+	for _, dir := range directions {
+		next := Point2{pos.x + dir.x, pos.y + dir.y}
+		if _, open := gridData.smallGrid[next]; open {
+			neighbors = append(neighbors, next)
 		}
 	}
+	return neighbors
+}
 
-	return maxFound - 1
+// a step is allowed from a slope tile only in the direction of the slope
+func canStep(gridData Grid, from, to Point2) bool {
+	dir, isSlope := slopeDirections[gridData.smallGrid[from].value]
+	return !isSlope || (to.x-from.x == dir.x && to.y-from.y == dir.y)
 }
 
-func explorePath(gridData Grid, path Path, paths *[]Path) int {
-	for !path.done {
-		//found the end of the maze for this path
-		if path.head.x == gridData.end.x && path.head.y == gridData.end.y {
-			path.history[path.head] = struct{}{}
-			path.done = true
-			return len(path.history)
+// LongestPath is the number of steps of the longest hike from the start to the end
+// that never visits a junction twice, with slopes the slope tiles can only be walked
+// downhill. It is a DFS with the visited junctions in a bitmask
+func (g JunctionGraph) LongestPath(ctx context.Context, slopes bool) (int, error) {
+	//the end has a single corridor in most maps, once at its other side going
+	//anywhere else than the end would block the way to the end
+	lastJunction, lastLength, toEnd := -1, 0, 0
+	for _, id := range g.adjacent[g.end] {
+		e := g.edges[id]
+		other, _ := e.from(g.end, slopes)
+		if _, ok := e.from(other, slopes); ok {
+			lastJunction, lastLength = other, e.length
+			toEnd++
 		}
+	}
+	if toEnd != 1 {
+		lastJunction = -1
+	}
 
-		firstPos := Point2{-1, -1}
-		for _, dir := range gridData.links[path.head] {
-			nextPos := Point2{path.head.x + dir.x, path.head.y + dir.y}
-
-			//prevent from going twice on the same cell
-			if _, inpath := path.history[nextPos]; inpath {
-				continue
-			}
-
-			if firstPos.x == -1 {
-				firstPos = nextPos
-			} else {
-				newPath := Path{
-					done:    false,
-					head:    nextPos,
-					history: map[Point2]struct{}{nextPos: {}},
-				}
-
-				for key := range path.history {
-					newPath.history[key] = struct{}{}
-				}
-				// CREATE A NEW PATH TO EXPLORE
-				*paths = append(*paths, newPath)
+	best := -1
+	calls := 0
+	var err error
+	var dfs func(node int, visited uint64, length int)
+	dfs = func(node int, visited uint64, length int) {
+		if err != nil {
+			return
+		}
+		calls++
+		if calls%(1<<16) == 0 {
+			if err = ctx.Err(); err != nil {
+				return
 			}
 		}
 
-		if firstPos.x != -1 {
-			path.head = firstPos
-			path.history[firstPos] = struct{}{}
-		} else {
-			path.done = true
+		if node == g.end {
+			best = max(best, length)
+			return
 		}
-	}
-	return -1
-}
-
-/*
-func printgrid(grid Grid) {
-	fmt.Println()
-	for y := 0; y < grid.bounds.y; y++ {
-		for x := 0; x < grid.bounds.x; x++ {
-			//SHOW DIR
-			if grid.fullGrid[Point2{x, y}].value == "#" {
-				fmt.Print(grid.fullGrid[Point2{x, y}].value)
-			} else {
-				if grid.fullGrid[Point2{x, y}].dir.x == 1 {
-					fmt.Print("\033[32m>\033[0m")
-				} else if grid.fullGrid[Point2{x, y}].dir.x == -1 {
-					fmt.Print("\033[32m<\033[0m")
-				} else if grid.fullGrid[Point2{x, y}].dir.y == 1 {
-					fmt.Print("\033[32mv\033[0m")
-				} else if grid.fullGrid[Point2{x, y}].dir.y == -1 {
-					fmt.Print("\033[32m^\033[0m")
-				} else {
-					fmt.Print(grid.fullGrid[Point2{x, y}].value)
-				}
+		if node == lastJunction {
+			best = max(best, length+lastLength)
+			return
+		}
+		for _, id := range g.adjacent[node] {
+			next, ok := g.edges[id].from(node, slopes)
+			if !ok || visited&(1<<next) != 0 {
+				continue
 			}
+			dfs(next, visited|1<<next, length+g.edges[id].length)
 		}
-		fmt.Println()
 	}
-	fmt.Println()
-}
+	dfs(g.start, 1<<g.start, 0)
 
-func printgridPath(grid Grid, path Path) {
-	fmt.Println()
-	for y := 0; y < grid.bounds.y; y++ {
-		for x := 0; x < grid.bounds.x; x++ {
-			if grid.fullGrid[Point2{x, y}].value == "#" {
-				fmt.Print(grid.fullGrid[Point2{x, y}].value)
-			} else {
-
-				if _, inpath := path.history[Point2{x, y}]; inpath {
-					fmt.Print("\033[32m", grid.fullGrid[Point2{x, y}].value, "\033[0m")
-				} else {
-					fmt.Print(grid.fullGrid[Point2{x, y}].value)
-				}
-			}
-		}
-		fmt.Println()
+	if err != nil {
+		return 0, err
 	}
-	fmt.Println()
-}*/
+	if best == -1 {
+		return 0, errors.New("no hike from the start reaches the end")
+	}
+	return best, nil
+}
@@ -138,26 +138,30 @@ What do you get if you add up the X, Y, and Z coordinates of that initial positi
 package Day24
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"math"
-	"os"
+	"math/big"
 	"strconv"
 	"strings"
 )
 
-func Day24() [2]int {
-	return [2]int{
-		d24p1(),
-		d24p2(),
-	}
+func init() {
+	solver.Register(24, solver.New(Parse, Part1, Part2))
 }
 
-func d24p1() int {
-	datas := loadData("./Day24/Ressources/day24_input.txt")
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	datas := input
 	sum := 0
 
 	for i := 0; i < len(datas)-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		for j := i + 1; j < len(datas); j++ {
 
 			vA := datas[i]
@@ -169,118 +173,230 @@ func d24p1() int {
 		}
 	}
 
-	return sum
+	return solver.Int(sum), nil
 }
 
-// solution by reddit user "mynt" https://www.reddit.com/r/adventofcode/comments/18pnycy/comment/kicuapd/?utm_source=share&utm_medium=web2x&context=3
-func d24p2() int {
-	//287704452860064, 121558528808556, 254224870158150 @ 23, 176, 68
-	//275586065064718, 113832932538934, 250412578315621 @ 231, 176, 108
-	//221521858324342, 147871529369018, 271954329484075 @ 233, 176, 20
-
-	vx0 := float64(23)
-	//vy0 := float64(176)
-	vz0 := float64(68)
-
-	vx1 := float64(231)
-	vy1 := float64(176)
-	vz1 := float64(108)
+// part 2, sum of the coordinates of the rock position
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	rock, err := ThrowRock(ctx, input)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	sum := new(big.Int)
+	for _, c := range rock.Position {
+		sum.Add(sum, c)
+	}
+	return solver.Big(sum), nil
+}
 
-	vx2 := float64(233)
-	vy2 := float64(176)
-	vz2 := float64(20)
+//_____________________________________________________________________________
+//______________________________________PART 2_________________________________
+//_____________________________________________________________________________
 
-	x0 := float64(287704452860064)
-	y0 := float64(121558528808556)
-	z0 := float64(254224870158150)
+// Rock is the integer position and velocity of the thrown rock, as x, y, z
+type Rock struct {
+	Position, Velocity [3]*big.Int
+}
 
-	x1 := float64(275586065064718)
-	y1 := float64(113832932538934)
-	z1 := float64(250412578315621)
+// ThrowRock finds the rock that hits every hailstone.
+// The rock P + t*V hits the hailstone p + t*v when (P - p) x (V - v) = 0. The non
+// linear term P x V is the same for every hailstone, the difference of this equation
+// for two hailstones is linear in P and V. Two pairs give 6 equations solved exactly
+// with rationals, the first triple of hailstones giving a regular system is used and
+// the rock is then checked against every hailstone
+func ThrowRock(ctx context.Context, hailstones Input) (Rock, error) {
+	if len(hailstones) < 3 {
+		return Rock{}, fmt.Errorf("need at least 3 hailstones to find the rock, found %d", len(hailstones))
+	}
 
-	x2 := float64(221521858324342)
-	y2 := float64(147871529369018)
-	z2 := float64(271954329484075)
+	for i := 0; i < len(hailstones); i++ {
+		if err := ctx.Err(); err != nil {
+			return Rock{}, err
+		}
+		for j := i + 1; j < len(hailstones); j++ {
+			for k := j + 1; k < len(hailstones); k++ {
+				system := append(pairEquations(hailstones[i], hailstones[j]), pairEquations(hailstones[i], hailstones[k])...)
+				solution, ok := solveLinear(system)
+				if !ok {
+					continue
+				}
+				rock, err := rockFromSolution(solution)
+				if err != nil {
+					return Rock{}, err
+				}
+				for h, hailstone := range hailstones {
+					if !rock.hits(hailstone) {
+						return Rock{}, fmt.Errorf("rock %v @ %v misses hailstone %d", rock.Position, rock.Velocity, h+1)
+					}
+				}
+				return rock, nil
+			}
+		}
+	}
+	return Rock{}, errors.New("no 3 hailstones give a single rock")
+}
 
-	vxr1 := vx1 - vx0
-	vzr1 := vz1 - vz0
-	vxr2 := vx2 - vx0
-	vzr2 := vz2 - vz0
+// get the coordinates of a point as exact big integers
+func bigCoords(p [3]int) [3]*big.Int {
+	return [3]*big.Int{big.NewInt(int64(p[0])), big.NewInt(int64(p[1])), big.NewInt(int64(p[2]))}
+}
 
-	xr1 := x1 - x0
-	yr1 := y1 - y0
-	zr1 := z1 - z0
+// cross product of two integer vectors
+func bigCross(a, b [3]*big.Int) [3]*big.Int {
+	mul := func(x, y *big.Int) *big.Int { return new(big.Int).Mul(x, y) }
+	return [3]*big.Int{
+		new(big.Int).Sub(mul(a[1], b[2]), mul(a[2], b[1])),
+		new(big.Int).Sub(mul(a[2], b[0]), mul(a[0], b[2])),
+		new(big.Int).Sub(mul(a[0], b[1]), mul(a[1], b[0])),
+	}
+}
 
-	xr2 := x2 - x0
-	yr2 := y2 - y0
-	zr2 := z2 - z0
+func bigSub(a, b [3]*big.Int) [3]*big.Int {
+	return [3]*big.Int{new(big.Int).Sub(a[0], b[0]), new(big.Int).Sub(a[1], b[1]), new(big.Int).Sub(a[2], b[2])}
+}
 
-	num := (yr2 * xr1 * vzr1) - (vxr1 * yr2 * zr1) + (yr1 * zr2 * vxr1) - (yr1 * xr2 * vzr1)
-	den := yr1 * ((vzr1 * vxr2) - (vxr1 * vzr2))
-	t2 := num / den
+// the 3 linear equations in (Px, Py, Pz, Vx, Vy, Vz) given by two hailstones a and b:
+// P x (va - vb) + (pa - pb) x V = pa x va - pb x vb.
+// Each row holds the 6 coefficients then the right hand side
+func pairEquations(a, b Vector3) [][7]*big.Rat {
+	pa, va := bigCoords(a.exactPos), bigCoords(a.exactDir)
+	pb, vb := bigCoords(b.exactPos), bigCoords(b.exactDir)
+	dv := bigSub(va, vb)
+	dp := bigSub(pa, pb)
+	rhs := bigSub(bigCross(pa, va), bigCross(pb, vb))
+
+	zero := new(big.Int)
+	neg := func(x *big.Int) *big.Int { return new(big.Int).Neg(x) }
+	coefficients := [3][6]*big.Int{
+		//P x dv = (Py dvz - Pz dvy, Pz dvx - Px dvz, Px dvy - Py dvx)
+		//dp x V = (dpy Vz - dpz Vy, dpz Vx - dpx Vz, dpx Vy - dpy Vx)
+		{zero, dv[2], neg(dv[1]), zero, neg(dp[2]), dp[1]},
+		{neg(dv[2]), zero, dv[0], dp[2], zero, neg(dp[0])},
+		{dv[1], neg(dv[0]), zero, neg(dp[1]), dp[0], zero},
+	}
 
-	num = (yr1 * xr2) + (yr1 * vxr2 * t2) - (yr2 * xr1)
-	den = yr2 * vxr1
-	t1 := num / den
+	rows := make([][7]*big.Rat, 3)
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 6; c++ {
+			rows[r][c] = new(big.Rat).SetInt(coefficients[r][c])
+		}
+		rows[r][6] = new(big.Rat).SetInt(rhs[r])
+	}
+	return rows
+}
 
-	cx1 := x1 + (t1 * vx1)
-	cy1 := y1 + (t1 * vy1)
-	cz1 := z1 + (t1 * vz1)
+// solve the 6x6 system with a Gauss-Jordan elimination, false if the system is singular
+func solveLinear(rows [][7]*big.Rat) ([6]*big.Rat, bool) {
+	solution := [6]*big.Rat{}
+	for col := 0; col < 6; col++ {
+		pivot := -1
+		for r := col; r < 6; r++ {
+			if rows[r][col].Sign() != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot == -1 {
+			return solution, false
+		}
+		rows[col], rows[pivot] = rows[pivot], rows[col]
 
-	cx2 := x2 + (t2 * vx2)
-	cy2 := y2 + (t2 * vy2)
-	cz2 := z2 + (t2 * vz2)
+		for r := 0; r < 6; r++ {
+			if r == col || rows[r][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Quo(rows[r][col], rows[col][col])
+			for c := col; c < 7; c++ {
+				rows[r][c] = new(big.Rat).Sub(rows[r][c], new(big.Rat).Mul(factor, rows[col][c]))
+			}
+		}
+	}
+	for r := 0; r < 6; r++ {
+		solution[r] = new(big.Rat).Quo(rows[r][6], rows[r][r])
+	}
+	return solution, true
+}
 
-	xm := (cx2 - cx1) / (t2 - t1)
-	ym := (cy2 - cy1) / (t2 - t1)
-	zm := (cz2 - cz1) / (t2 - t1)
+// the rock must have an integer position and velocity
+func rockFromSolution(solution [6]*big.Rat) (Rock, error) {
+	rock := Rock{}
+	for i, v := range solution {
+		if !v.IsInt() {
+			return Rock{}, fmt.Errorf("the rock is not on integer coordinates, found %v", v.RatString())
+		}
+		if i < 3 {
+			rock.Position[i] = new(big.Int).Set(v.Num())
+		} else {
+			rock.Velocity[i-3] = new(big.Int).Set(v.Num())
+		}
+	}
+	return rock, nil
+}
 
-	xc := cx1 - (xm * t1)
-	yc := cy1 - (ym * t1)
-	zc := cz1 - (zm * t1)
+// check that the rock and the hailstone are at the same place at the same time t >= 0
+func (r Rock) hits(h Vector3) bool {
+	dp := bigSub(bigCoords(h.exactPos), r.Position)
+	dv := bigSub(r.Velocity, bigCoords(h.exactDir))
 
-	return int(xc + yc + zc)
+	//same place at t when dp = t * dv, the vectors must be parallel and point the same way
+	for _, c := range bigCross(dp, dv) {
+		if c.Sign() != 0 {
+			return false
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if dv[i].Sign() != 0 {
+			return dp[i].Sign() == 0 || dp[i].Sign() == dv[i].Sign()
+		}
+	}
+	//same velocity, they meet only if they start together
+	return dp[0].Sign() == 0 && dp[1].Sign() == 0 && dp[2].Sign() == 0
 }
 
 //_____________________________________________________________________________
 //______________________________________PART 1_________________________________
 //_____________________________________________________________________________
 
-func loadData(path string) []Vector3 {
-	//open text file
-	file, err := os.Open(path)
-	if err != nil {
-		panic(err)
-	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			panic(err)
-		}
-	}()
+// Input is the list of hailstones, position and velocity of each
+type Input []Vector3
 
+// Parse reads the position and velocity of every hailstone
+func Parse(input io.Reader) (Input, error) {
 	//scan through the file with scanner
-	scanner := bufio.NewScanner(file)
-	datas := []Vector3{}
+	scanner := bufio.NewScanner(input)
+	datas := Input{}
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		//Each line of text uses the format px py pz @ vx vy vz
 		twoPart := strings.Split(scanner.Text(), " @ ")
-		pos := strings.Split(twoPart[0], ", ")
-		dir := strings.Split(twoPart[1], ", ")
-
-		pX, errPx := strconv.Atoi(pos[0])
-		pY, errPy := strconv.Atoi(pos[1])
-		pZ, errPz := strconv.Atoi(pos[2])
-		dX, errDx := strconv.Atoi(dir[0])
-		dY, errDy := strconv.Atoi(dir[1])
-		dZ, errDz := strconv.Atoi(dir[2])
-
-		if errPx != nil || errPy != nil || errPz != nil {
-			log.Fatal("[loadData] error while parsing pos to int (x,y,z):", errPx, errPy, errPz)
+		if len(twoPart) != 2 {
+			return nil, solver.Errorf(lineNumber, 0, "expected position @ velocity in line: %q", scanner.Text())
 		}
 
-		if errDx != nil || errDy != nil || errDz != nil {
-			log.Fatal("[loadData] error while parsing dir to int (x,y,z):", errDx, errDy, errDz)
+		//pX, pY, pZ, dX, dY, dZ
+		values := [6]int{}
+		for i, part := range twoPart {
+			coords := strings.Split(part, ", ")
+			if len(coords) != 3 {
+				return nil, solver.Errorf(lineNumber, 0, "expected 3 coordinates on each side of @ in line: %q", scanner.Text())
+			}
+			//the velocity starts after the position and " @ "
+			column := 1
+			if i == 1 {
+				column = len(twoPart[0]) + 4
+			}
+			for j, coord := range coords {
+				trimmed := strings.TrimLeft(coord, " ")
+				v, err := strconv.Atoi(trimmed)
+				if err != nil {
+					return nil, solver.NewParseError(lineNumber, column+len(coord)-len(trimmed), err)
+				}
+				values[i*3+j] = v
+				column += len(coord) + 2
+			}
 		}
+		pX, pY, pZ, dX, dY, dZ := values[0], values[1], values[2], values[3], values[4], values[5]
 
 		vector := Vector3{
 			pos: Point3{
@@ -293,14 +409,16 @@ func loadData(path string) []Vector3 {
 				y: float64(dY),
 				z: float64(dZ),
 			},
+			exactPos: [3]int{pX, pY, pZ},
+			exactDir: [3]int{dX, dY, dZ},
 		}
 
 		datas = append(datas, vector)
 	}
 	if scanner.Err() != nil {
-		panic(scanner.Err())
+		return nil, scanner.Err()
 	}
-	return datas
+	return datas, nil
 }
 
 func findLineEquation(v3 Vector3) (float64, float64) {
@@ -376,8 +494,11 @@ type Point3 struct {
 	x, y, z float64
 }
 
+// Vector3 is a hailstone, pos and dir are used by the float geometry of part 1,
+// the exact integers of the input are kept for part 2
 type Vector3 struct {
-	pos, dir Point3
+	pos, dir           Point3
+	exactPos, exactDir [3]int
 }
 
 func dotProduct(a, b Point3, dim int) float64 {
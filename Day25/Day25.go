@@ -75,17 +75,241 @@ two groups together?
 
 package day25
 
-func Day25() [2]int {
-	return [2]int{
-		d25p1(),
-		d25p2(),
+import (
+	"AdventOfCode/Utils/solver"
+	"bufio"
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+func init() {
+	solver.Register(25, solver.New(Parse, Part1, Part2))
+}
+
+// Input is the wiring diagram, each component with the components
+// listed after its colon
+type Input map[string][]string
+
+// Parse reads the wiring diagram, one "name: a b c" line per component
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
+	diagram := Input{}
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		split := strings.Split(scanner.Text(), ": ")
+		if len(split) != 2 || split[0] == "" {
+			return nil, solver.Errorf(lineNumber, 0, "expected name: components in line: %q", scanner.Text())
+		}
+		diagram[split[0]] = append(diagram[split[0]], strings.Fields(split[1])...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
+
+	return diagram, nil
 }
 
-func d25p1() int {
-	return 0
+// number of wires to disconnect, given by the puzzle
+const puzzleWires = 3
+
+// part 1, multiply the sizes of the two groups left once the three wires are cut
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	cut, err := MinCut(ctx, input)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	if len(cut.Wires) != puzzleWires {
+		return solver.Result{}, fmt.Errorf("the minimum cut disconnects %d wires, expected %d", len(cut.Wires), puzzleWires)
+	}
+	return solver.Int(cut.Product), nil
 }
 
-func d25p2() int {
-	return 0
+// there is no second puzzle on day 25, the wires of the cut are given by MinCut
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	return solver.Unsolved(), nil
+}
+
+// Wire connects two components, the names are in alphabetical order
+type Wire [2]string
+
+// Cut splits the components in two groups by disconnecting the fewest wires
+type Cut struct {
+	Sizes   [2]int // number of components in each group, the group of the first component name comes first
+	Product int    // Sizes[0] * Sizes[1]
+	Wires   []Wire // the disconnected wires, sorted
+}
+
+// MinCut finds the global minimum cut of the diagram with the Stoer-Wagner algorithm.
+// Each phase orders the super nodes by maximum adjacency using a heap, the last two
+// nodes of the order give a candidate cut and are merged before the next phase
+func MinCut(ctx context.Context, diagram Input) (Cut, error) {
+	//give an index to every component, sorted so that the result does not depend on the map order
+	names := []string{}
+	index := map[string]int{}
+	addName := func(name string) {
+		if _, ok := index[name]; !ok {
+			index[name] = -1
+			names = append(names, name)
+		}
+	}
+	for name, others := range diagram {
+		addName(name)
+		for _, o := range others {
+			addName(o)
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		index[name] = i
+	}
+
+	n := len(names)
+	if n < 2 {
+		return Cut{}, fmt.Errorf("need at least 2 components to cut the diagram, found %d", n)
+	}
+
+	//adj holds the weight of the wires between super nodes, members the components merged in each
+	adj := make([]map[int]int, n)
+	members := make([][]int, n)
+	for i := range adj {
+		adj[i] = map[int]int{}
+		members[i] = []int{i}
+	}
+	for name, others := range diagram {
+		for _, o := range others {
+			a, b := index[name], index[o]
+			if a == b {
+				continue
+			}
+			adj[a][b]++
+			adj[b][a]++
+		}
+	}
+
+	active := make([]bool, n)
+	for i := range active {
+		active[i] = true
+	}
+	weight := make([]int, n)
+	inOrder := make([]bool, n)
+
+	bestCut := -1
+	bestGroup := []int{}
+	for phase := n; phase > 1; phase-- {
+		if err := ctx.Err(); err != nil {
+			return Cut{}, err
+		}
+
+		//maximum adjacency order, every active node starts with a weight of 0
+		queue := &nodeHeap{}
+		for v := 0; v < n; v++ {
+			if active[v] {
+				weight[v] = 0
+				inOrder[v] = false
+				*queue = append(*queue, heapNode{v, 0})
+			}
+		}
+		heap.Init(queue)
+
+		prev, last := -1, -1
+		for added := 0; added < phase; {
+			top := heap.Pop(queue).(heapNode)
+			//skip the stale entries of nodes whose weight went up since they were pushed
+			if inOrder[top.node] || top.weight != weight[top.node] {
+				continue
+			}
+			inOrder[top.node] = true
+			added++
+			prev, last = last, top.node
+			for nb, c := range adj[top.node] {
+				if !inOrder[nb] {
+					weight[nb] += c
+					heap.Push(queue, heapNode{nb, weight[nb]})
+				}
+			}
+		}
+
+		//the cut of the phase separates the last node from all the others
+		if bestCut == -1 || weight[last] < bestCut {
+			bestCut = weight[last]
+			bestGroup = append([]int{}, members[last]...)
+		}
+
+		//merge the last node in the one added before it
+		for nb, c := range adj[last] {
+			delete(adj[nb], last)
+			if nb == prev {
+				continue
+			}
+			adj[prev][nb] += c
+			adj[nb][prev] += c
+		}
+		adj[last] = nil
+		members[prev] = append(members[prev], members[last]...)
+		members[last] = nil
+		active[last] = false
+	}
+
+	//the group of the first component name is reported first
+	inGroup := make([]bool, n)
+	for _, v := range bestGroup {
+		inGroup[v] = true
+	}
+	size := len(bestGroup)
+	cut := Cut{Sizes: [2]int{n - size, size}}
+	if inGroup[0] {
+		cut.Sizes = [2]int{size, n - size}
+	}
+	cut.Product = cut.Sizes[0] * cut.Sizes[1]
+
+	for name, others := range diagram {
+		for _, o := range others {
+			if inGroup[index[name]] != inGroup[index[o]] {
+				w := Wire{name, o}
+				if w[1] < w[0] {
+					w = Wire{o, name}
+				}
+				cut.Wires = append(cut.Wires, w)
+			}
+		}
+	}
+	sort.Slice(cut.Wires, func(i, j int) bool {
+		if cut.Wires[i][0] != cut.Wires[j][0] {
+			return cut.Wires[i][0] < cut.Wires[j][0]
+		}
+		return cut.Wires[i][1] < cut.Wires[j][1]
+	})
+
+	return cut, nil
+}
+
+// heapNode is a super node with its weight when it was pushed
+type heapNode struct {
+	node, weight int
+}
+
+// nodeHeap is a max heap on the weight, ties go to the lowest node to keep the order stable
+type nodeHeap []heapNode
+
+func (h nodeHeap) Len() int { return len(h) }
+func (h nodeHeap) Less(i, j int) bool {
+	if h[i].weight != h[j].weight {
+		return h[i].weight > h[j].weight
+	}
+	return h[i].node < h[j].node
+}
+func (h nodeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x any)   { *h = append(*h, x.(heapNode)) }
+func (h *nodeHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
 }
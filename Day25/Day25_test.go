@@ -0,0 +1,78 @@
+package day25
+
+import (
+	"AdventOfCode/Utils/solver"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const example = `jqt: rhn xhk nvd
+rsh: frs pzl lsr
+xhk: hfx
+cmg: qnr nvd lhk bvb
+rhn: xhk bvb hfx
+bvb: xhk hfx
+pzl: lsr hfx nvd
+qnr: nvd
+ntq: jqt hfx bvb xhk
+nvd: lhk
+lsr: lhk
+rzs: qnr cmg lsr rsh
+frs: qnr lhk lsr`
+
+func parseExample(t *testing.T, input string) Input {
+	t.Helper()
+	diagram, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return diagram
+}
+
+func TestMinCut(t *testing.T) {
+	cut, err := MinCut(context.Background(), parseExample(t, example))
+	if err != nil {
+		t.Fatalf("MinCut: %v", err)
+	}
+
+	if cut.Sizes != [2]int{9, 6} && cut.Sizes != [2]int{6, 9} {
+		t.Errorf("Sizes = %v, expected 9 and 6", cut.Sizes)
+	}
+	if cut.Product != 54 {
+		t.Errorf("Product = %d, expected 54", cut.Product)
+	}
+	expected := []Wire{{"bvb", "cmg"}, {"hfx", "pzl"}, {"jqt", "nvd"}}
+	if !reflect.DeepEqual(cut.Wires, expected) {
+		t.Errorf("Wires = %v, expected %v", cut.Wires, expected)
+	}
+}
+
+func TestParts(t *testing.T) {
+	input := parseExample(t, example)
+
+	result, err := Part1(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Part1: %v", err)
+	}
+	if result.String() != "54" {
+		t.Errorf("Part1 = %s, expected 54", result)
+	}
+
+	result, err = Part2(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Part2: %v", err)
+	}
+	if result.Kind() != solver.KindUnsolved {
+		t.Errorf("Part2 = %s (%s), expected an unsolved result", result, result.Kind())
+	}
+}
+
+// a triangle with a tail is cut by a single wire, not the three of the puzzle
+func TestPart1WrongCut(t *testing.T) {
+	input := parseExample(t, "a: b c\nb: c\nc: d")
+	if result, err := Part1(context.Background(), input); err == nil {
+		t.Errorf("Part1 = %s, expected an error", result)
+	}
+}
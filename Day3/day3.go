@@ -86,18 +86,35 @@ What is the sum of all of the gear ratios in your engine schematic?
 package Day3
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"io"
 	"strconv"
 	"unicode"
 )
 
-func Day3() [2]int {
-	return [2]int{
-		d3p1(),
-		d3p2(),
+func init() {
+	solver.Register(3, solver.New(Parse, Part1, Part2))
+}
+
+// Input is the engine schematic as a 2D array of characters
+type Input [][]rune
+
+// Parse reads the engine schematic into a 2D array
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
+	var slice2D Input
+
+	for scanner.Scan() {
+		slice2D = append(slice2D, []rune(scanner.Text()))
 	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return slice2D, nil
 }
 
 // check if a specific position contains a character different from '.' and not a digit
@@ -112,21 +129,21 @@ func evaluatePos(slice [][]rune, predicate bool, x int, y int) bool {
 }
 
 // search if the array all the digit composing a number based on input coordinates
-func getFullNumber(slice2D [][]rune, x int, y int) int {
+func getFullNumber(slice2D [][]rune, x int, y int) (int, error) {
 	output := -1
 
 	//early return in case of wrong data input
 	if len(slice2D) == 0 {
-		return output
+		return output, nil
 	}
 	if x < 0 || x > len(slice2D) {
-		return output
+		return output, nil
 	}
 	if len(slice2D[x]) == 0 {
-		return output
+		return output, nil
 	}
 	if !unicode.IsDigit(slice2D[x][y]) {
-		return output
+		return output, nil
 	}
 
 	sNumber := string(slice2D[x][y])
@@ -153,40 +170,15 @@ func getFullNumber(slice2D [][]rune, x int, y int) int {
 		ny++
 	}
 
-	if n, err := strconv.Atoi(sNumber); err == nil {
-		return n
-	} else {
-		log.Fatal(err)
-	}
-	return output
-}
-
-func d3p1() int {
-	//step 0 open the file
-	file, err := os.Open("./Day3/Ressources/day3_input.txt")
-
+	n, err := strconv.Atoi(sNumber)
 	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
-
-	//step 1 get document size in order to initialize the 2D array
-	var slice2D [][]rune
-
-	for scanner.Scan() {
-		slice2D = append(slice2D, []rune(scanner.Text()))
+		return output, solver.NewParseError(x+1, ny-len(sNumber)+1, err)
 	}
+	return n, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	slice2D := input
 
 	//step 2 walk over and find numbers and their surroundings
 	sum := 0
@@ -194,6 +186,9 @@ func d3p1() int {
 	valid := false
 
 	for x := 0; x < len(slice2D); x++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		for y := 0; y < len(slice2D[x]); y++ {
 			if unicode.IsDigit(slice2D[x][y]) {
 				currentNumber += string(slice2D[x][y])
@@ -236,41 +231,20 @@ func d3p1() int {
 		valid = false
 	}
 
-	return sum
+	return solver.Int(sum), nil
 }
 
-func d3p2() int {
-	//step 0 open the file
-	file, err := os.Open("./Day3/Ressources/day3_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
-
-	//step 1 get document size in order to initialize the 2D array
-	var slice2D [][]rune
-
-	for scanner.Scan() {
-		slice2D = append(slice2D, []rune(scanner.Text()))
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	slice2D := input
 
 	//will be the output of the function, sum of all gear ratio found
 	sum := 0
 
 	//step 2 walk over and find numbers and their surroundings
 	for x := 0; x < len(slice2D); x++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		for y := 0; y < len(slice2D[x]); y++ {
 
 			//find a gear
@@ -313,7 +287,11 @@ func d3p2() int {
 							isOverNumber = true
 							//get the full number from the current digit
 							//(-1 on the neighbourt pos to convert to global pos)
-							gearRatio *= getFullNumber(slice2D, x+nx-1, y+ny-1)
+							n, err := getFullNumber(slice2D, x+nx-1, y+ny-1)
+							if err != nil {
+								return solver.Result{}, err
+							}
+							gearRatio *= n
 						} else if isOverNumber && !unicode.IsDigit(neighbour[nx][ny]) {
 							isOverNumber = false
 							countNumbers++
@@ -332,5 +310,5 @@ func d3p2() int {
 			}
 		}
 	}
-	return sum
+	return solver.Int(sum), nil
 }
@@ -124,10 +124,11 @@ set of scratchcards, how many total scratchcards do you end up with?
 package Day4
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
+	"context"
+	"io"
 	"math"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -140,24 +141,54 @@ type card struct {
 	count       int
 }
 
-func Day4() [2]int {
-	return [2]int{
-		d4p1(),
-		d4p2(),
+// Input is the pile of scratchcards
+type Input []card
+
+func init() {
+	solver.Register(4, solver.New(Parse, Part1, Part2))
+}
+
+// Parse reads every scratchcard of the pile
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
+	cards := Input{}
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		newCard, err := ExtractCardinfo(scanner.Text(), lineNumber)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, newCard)
 	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
 }
 
 // read a line of text representing a card
 // extract all the ID, wining numbers, player numbers, score and count
-func ExtractCardinfo(text string) card {
-	rawid := strings.Split(strings.Split(text, ":")[0], " ")
+// lineNumber is only used to locate errors in the input
+func ExtractCardinfo(text string, lineNumber int) (card, error) {
+	idAndNumbers := strings.Split(text, ":")
+	if len(idAndNumbers) != 2 {
+		return card{}, solver.Errorf(lineNumber, 0, "no ':' separator found in card: %q", text)
+	}
+	rawid := strings.Split(idAndNumbers[0], " ")
 	id, err := strconv.Atoi(rawid[len(rawid)-1])
 	if err != nil {
-		log.Fatal(err)
+		return card{}, solver.NewParseError(lineNumber, len(idAndNumbers[0])-len(rawid[len(rawid)-1])+1, err)
+	}
+	numbers := strings.Split(idAndNumbers[1], "|")
+	if len(numbers) != 2 {
+		return card{}, solver.Errorf(lineNumber, 0, "no '|' separator found in card: %q", text)
 	}
-	numbers := strings.Split(text, ":")[1]
-	winningNumsString := strings.Split(numbers, "|")[0]
-	myNumsString := strings.Split(numbers, "|")[1]
+	winningNumsString := numbers[0]
+	myNumsString := numbers[1]
 
 	winningNums := strings.Split(winningNumsString, " ")
 	myNums := strings.Split(myNumsString, " ")
@@ -180,7 +211,7 @@ func ExtractCardinfo(text string) card {
 		n, err := strconv.Atoi(winningNums[i])
 
 		if err != nil {
-			log.Fatal(err)
+			return card{}, solver.NewParseError(lineNumber, solver.Column(text, winningNums[i]), err)
 		}
 		_, ok := newCard.winningNums[n]
 		if ok {
@@ -199,7 +230,7 @@ func ExtractCardinfo(text string) card {
 		n, err := strconv.Atoi(myNums[i])
 
 		if err != nil {
-			log.Fatal(err)
+			return card{}, solver.NewParseError(lineNumber, strings.Index(text, "|")+1+solver.Column(myNumsString, myNums[i]), err)
 		}
 
 		_, ok := newCard.playerNums[n]
@@ -210,40 +241,17 @@ func ExtractCardinfo(text string) card {
 		}
 	}
 
-	return newCard
+	return newCard, nil
 }
 
-func d4p1() int {
-
-	file, err := os.Open("./Day4/Ressources/day4_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
-
-	var cards []card
-
-	//extract info about all games
-	for scanner.Scan() {
-		cards = append(cards, ExtractCardinfo(scanner.Text()))
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
 	//calculate score
 	sum := 0
 
-	for _, g := range cards {
+	for _, g := range input {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		count := 0
 		for winKey := range g.winningNums {
 			for playerKey := range g.playerNums {
@@ -259,30 +267,15 @@ func d4p1() int {
 		}
 	}
 
-	return sum
+	return solver.Int(sum), nil
 }
 
-func d4p2() int {
-	file, err := os.Open("./Day4/Ressources/day4_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
-
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
 	//this time we need to be able to access the card by ID fast so we make a map
 	var cards = make(map[int]card, 0)
 
-	//extract info about all games
-	for scanner.Scan() {
-		newCard := ExtractCardinfo(scanner.Text())
+	//index all games by ID
+	for _, newCard := range input {
 		_, ok := cards[newCard.cardID]
 		if ok {
 			newCard.count = cards[newCard.cardID].count + 1
@@ -292,14 +285,13 @@ func d4p2() int {
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
 	sum := 0
 
 	//like first exercise we get the amount of win numbers that we have on the player size
 	for i := 1; i <= len(cards); i++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		c, ok := cards[i]
 		if ok {
 			winCount := 0
@@ -330,5 +322,5 @@ func d4p2() int {
 		}
 	}
 
-	return sum
+	return solver.Int(sum), nil
 }
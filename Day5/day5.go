@@ -166,23 +166,17 @@ to any of the initial seed numbers?
 package Day5
 
 import (
-	utils "AdventOfCode/Utils"
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
+	"context"
+	"fmt"
+	"io"
 	"math"
-	"os"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
 )
 
-// main representation of the input from the exercise (seeds)
-type block struct {
-	start         int
-	end           int
-	slicedAlready bool
-}
-
 // main representation of the input categories filters (soil, water, etc..)
 type blockFilter struct {
 	start   int
@@ -190,23 +184,21 @@ type blockFilter struct {
 	newBase int
 }
 
-func Day5() [2]int {
-	return [2]int{
-		d5p1(),
-		d5p2(),
-	}
+// Input is the almanac: the seeds list and the 7 layers of filters
+// (seed-to-soil, soil-to-fertilizer, ..., humidity-to-location)
+type Input struct {
+	seeds   []int
+	filters [][]blockFilter
+}
+
+func init() {
+	solver.Register(5, solver.New(Parse, Part1, Part2))
 }
 
 // extract the txt input into a slice fo seeds :[]int ;and a slice for each category
 // the category slices contains a slice of filters : [][]blockFilter
-func Extract() ([][]blockFilter, []int) {
-	file, err := os.Open("./Day5/Ressources/day5_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	scanner := bufio.NewScanner(file)
+func Parse(input io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(input)
 
 	seedsList := make([]int, 0)
 
@@ -223,15 +215,21 @@ func Extract() ([][]blockFilter, []int) {
 	lastLine := ""
 	currentCategory := -1
 	lineID := 1
+	lineNumber := 0
 
 	for scanner.Scan() {
+		lineNumber++
 		if lineID == 1 {
 			//get seed list
-			split := strings.Split(strings.Split(scanner.Text(), "seeds: ")[1], " ")
+			seedsLine := strings.Split(scanner.Text(), "seeds: ")
+			if len(seedsLine) != 2 {
+				return Input{}, solver.Errorf(lineNumber, 0, "no seeds list found in line: %q", scanner.Text())
+			}
+			split := strings.Split(seedsLine[1], " ")
 			for i := 0; i < len(split); i++ {
 				seedId, err := strconv.Atoi(split[i])
 				if err != nil {
-					log.Fatal(err)
+					return Input{}, solver.NewParseError(lineNumber, len("seeds: ")+solver.Column(seedsLine[1], split[i]), err)
 				}
 				seedsList = append(seedsList, seedId)
 			}
@@ -240,28 +238,28 @@ func Extract() ([][]blockFilter, []int) {
 			if scanner.Text() != "" {
 				if lastLine == "" {
 					currentCategory++
+					if currentCategory >= len(categoryMap) {
+						return Input{}, solver.Errorf(lineNumber, 0, "too many categories, found: %q", scanner.Text())
+					}
 					lastLine = scanner.Text()
 					continue
 				}
 
 				line := strings.Split(scanner.Text(), " ")
 				if len(line) != 3 {
-					log.Fatal("no 3 numbers found in line:\"" + scanner.Text() + "\"")
+					return Input{}, solver.Errorf(lineNumber, 0, "no 3 numbers found in line: %q", scanner.Text())
 				}
 
-				dst, err1 := strconv.Atoi(line[0])
-				src, err2 := strconv.Atoi(line[1])
-				size, err3 := strconv.Atoi(line[2])
-
-				if err1 != nil {
-					log.Fatal(err1)
-				}
-				if err2 != nil {
-					log.Fatal(err2)
-				}
-				if err3 != nil {
-					log.Fatal(err3)
+				//dst, src, size
+				numbers := [3]int{}
+				for i := range numbers {
+					n, err := strconv.Atoi(line[i])
+					if err != nil {
+						return Input{}, solver.NewParseError(lineNumber, solver.Column(scanner.Text(), line[i]), err)
+					}
+					numbers[i] = n
 				}
+				dst, src, size := numbers[0], numbers[1], numbers[2]
 
 				filter := blockFilter{
 					start:   src,
@@ -278,286 +276,185 @@ func Extract() ([][]blockFilter, []int) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return Input{}, err
 	}
 
-	return categoryMap, seedsList
+	return Input{seeds: seedsList, filters: categoryMap}, nil
 }
 
-// main logic for the conversion used in part 1 of the exercise
-// it work on the list of seed as if each element is a seed
-// unlike part2 that has seedID+range
-func Part1Converter(filters []blockFilter, key int) int {
-	result := -1
-
-	for filterID := 0; filterID < len(filters); filterID++ {
-		if key >= filters[filterID].start && key < filters[filterID].end {
-			//       destination range start +  position - source range start
-			result = filters[filterID].newBase + key - filters[filterID].start
-			break
-		}
-	}
-
-	if result == -1 {
-		return key
-	} else {
-		return result
+// core logic of part1, the lowest location of the seeds
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	almanac, err := input.Almanac()
+	if err != nil {
+		return solver.Result{}, err
 	}
-}
-
-// core logic of part1 return the result in print
-func d5p1() int {
-	category, seeds := Extract()
 
 	min := math.MaxInt
-	for i := 0; i < len(seeds); i++ {
-		s := Part1Converter(category[0], seeds[i])
-		s = Part1Converter(category[1], s)
-		s = Part1Converter(category[2], s)
-		s = Part1Converter(category[3], s)
-		s = Part1Converter(category[4], s)
-		s = Part1Converter(category[5], s)
-		s = Part1Converter(category[6], s)
-
-		if s < min {
+	for i := 0; i < len(input.seeds); i++ {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
+		if s := almanac.Map(input.seeds[i]); s < min {
 			min = s
 		}
 	}
 
-	return min
+	return solver.Int(min), nil
 }
 
-// take 2 block and merge them so the ranges expressed are the smallest possible
-// Consider {-1,-1} as a void part
-func blockMerger(a block, b block) [2]block {
-	output := [2]block{
-		{
-			start: -1,
-			end:   -1,
-		},
-		{
-			start: -1,
-			end:   -1,
-		},
+// core logic of part 2, the seeds come in pairs of start and range,
+// the lowest location of each range is queried on the composed map
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	seeds := input.seeds
+	if len(seeds)%2 != 0 {
+		return solver.Result{}, solver.Errorf(1, 0, "seeds need to come in pairs of start and range, found %d numbers", len(seeds))
 	}
 
-	switch code, logMsg := utils.CheckIntersect(a.start, a.end, b.start, b.end); code {
-	case 0:
-		output[0] = a
-		output[1] = b
-	case 1:
-		output[0] = b
-	case 2:
-		output[0].start = a.start
-		output[0].end = b.end
-	case 3:
-		output[0].start = b.start
-		output[0].end = a.end
-	case 4:
-		output[0].start = a.start
-		output[0].end = b.end
-	case 5:
-		output[0].start = b.start
-		output[0].end = a.end
-	case 6:
-		output[0] = a
-	case 7:
-		output[0].start = a.start
-		output[0].end = b.end
-	case 8:
-		output[0].start = b.start
-		output[0].end = a.end
-	case -1:
-		log.Fatal(logMsg)
+	almanac, err := input.Almanac()
+	if err != nil {
+		return solver.Result{}, err
 	}
 
-	return output
-}
-
-// takes in 1 block and one filter and cut out the part of the block that fit the filter
-// will return all bits of the original bloc after the cut, expressed by their new
-// coordinates / range.
-// Consider {-1,-1} as a void part
-func filterCutBlock(a block, b blockFilter) []block {
-	output := []block{
-		{
-			start: -1,
-			end:   -1,
-		},
-		{
-			start: -1,
-			end:   -1,
-		},
-		{
-			start: -1,
-			end:   -1,
-		},
+	min := math.MaxInt
+	for seedID := 0; seedID < len(seeds); seedID += 2 {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
+		if seeds[seedID+1] <= 0 {
+			continue
+		}
+		if s := almanac.MinOver(seeds[seedID], seeds[seedID]+seeds[seedID+1]-1); s < min {
+			min = s
+		}
 	}
+	return solver.Int(min), nil
+}
 
-	switch code, logMsg := utils.CheckIntersect(a.start, a.end, b.start, b.end); code {
-	case 0:
-		output[1].start = a.start
-		output[1].end = a.end
-	case 1:
-		output[0].start = a.start
-		output[0].end = a.end
-	case 2:
-		output[0].start = a.end
-		output[0].end = a.end
-		output[1].start = a.start
-		output[1].end = output[0].start - 1
-	case 3:
-		output[0].start = a.start
-		output[0].end = a.start
-		output[1].start = output[0].end - 1
-		output[1].end = a.end
-	case 4:
-		output[0].start = b.start
-		output[0].end = a.end
-		output[1].start = a.start
-		output[1].end = output[0].start - 1
-	case 5:
-		output[0].start = a.start
-		output[0].end = b.end
-		output[1].start = output[0].end + 1
-		output[1].end = a.end
-	case 6:
-		output[0].start = b.start
-		output[0].end = b.end
-		output[1].start = a.start
-		output[1].end = output[0].start - 1
-		output[2].start = output[0].end + 1
-		output[2].end = a.end
-	case -1:
-		log.Fatal(logMsg)
-	}
+// values covered by an IntervalMap, wide enough for any almanac and far enough
+// from the int limits so that adding an offset never overflows
+const (
+	domainMin = math.MinInt / 4
+	domainMax = math.MaxInt / 4
+)
 
-	return output
+// segment maps the values from start to end (inclusive) to value + offset
+type segment struct {
+	start, end, offset int
 }
 
-// convert an input range START END to a filter map START END NEWBASE
-func filterConversion(inputValue int, filterSrc int, filterDst int) int {
-	return inputValue - filterSrc + filterDst
+// IntervalMap is a piecewise-linear map of the integers: a sorted list of segments
+// covering the whole domain without overlap, each adding its offset to the values
+type IntervalMap struct {
+	segments []segment
 }
 
-// takes in a slice and will compare each elements to merge and remove overlaps
-// return the same slice with hopefully less elements
-func checkForMerge(slice []block) []block {
-	for aBlockID := 0; aBlockID < len(slice); aBlockID++ {
-		for bBlockID := aBlockID + 1; bBlockID < len(slice); bBlockID++ {
-			merge := blockMerger(slice[aBlockID], slice[bBlockID])
-			slice[aBlockID].start = merge[0].start
-			slice[aBlockID].end = merge[0].end
-			if merge[1].start != -1 {
-				slice[bBlockID].start = merge[1].start
-				slice[bBlockID].end = merge[1].end
-			} else {
-				//swaping unwanted ID with last and shrink the slice size by 1
-				slice[bBlockID], slice[len(slice)-1] = slice[len(slice)-1], slice[bBlockID]
-				slice = slice[:len(slice)-1]
-				bBlockID--
-			}
+// build the map of one layer of filters, the values out of every filter keep their number
+func newIntervalMap(filters []blockFilter) (IntervalMap, error) {
+	sorted := append([]blockFilter{}, filters...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+
+	m := IntervalMap{}
+	next := domainMin
+	for _, f := range sorted {
+		if f.end < f.start {
+			continue
+		}
+		if f.start < next {
+			return IntervalMap{}, fmt.Errorf("filters %d-%d overlap the previous filter", f.start, f.end)
 		}
+		if f.start > next {
+			m.segments = append(m.segments, segment{next, f.start - 1, 0})
+		}
+		m.segments = append(m.segments, segment{f.start, f.end, f.newBase - f.start})
+		next = f.end + 1
 	}
-	return slice
+	m.segments = append(m.segments, segment{next, domainMax, 0})
+	return m, nil
 }
 
-// core logic of part 2, will return the result as print
-func d5p2() int {
-	filters, seeds := Extract()
+// Seeds returns the numbers of the seeds line
+func (input Input) Seeds() []int {
+	return append([]int{}, input.seeds...)
+}
 
-	if len(seeds)%2 != 0 {
-		log.Fatal("ERROR : SEED/RANGE broken, needs to be a pair amount of numbers")
+// Almanac folds the 7 layers of filters in one map from seed to location
+func (input Input) Almanac() (IntervalMap, error) {
+	m := IntervalMap{segments: []segment{{domainMin, domainMax, 0}}}
+	for i, layer := range input.filters {
+		next, err := newIntervalMap(layer)
+		if err != nil {
+			return IntervalMap{}, fmt.Errorf("category %d: %w", i+1, err)
+		}
+		m = m.Then(next)
 	}
+	return m, nil
+}
 
-	blocks := []block{}
-
-	//convert seed list to blocks
-	for seedID := 0; seedID < len(seeds); seedID += 2 {
-		newBlock := block{
-			start: seeds[seedID],
-			end:   seeds[seedID] + seeds[seedID+1] - 1,
+// Then returns the map of x to next.Map(m.Map(x))
+func (m IntervalMap) Then(next IntervalMap) IntervalMap {
+	out := IntervalMap{}
+	for _, s := range m.segments {
+		//the image of the segment is cut by the segments of next
+		lo, hi := s.start+s.offset, s.end+s.offset
+		for _, t := range next.overlapping(lo, hi) {
+			out.add(segment{max(lo, t.start) - s.offset, min(hi, t.end) - s.offset, s.offset + t.offset})
 		}
-		blocks = append(blocks, newBlock)
 	}
+	return out
+}
 
-	//multi threading the scan
-	var wg sync.WaitGroup
-	multithreadLenght := len(blocks)
-	wg.Add(len(blocks))
-	packetOutput := []int{}
-
-	for blockID := 0; blockID < multithreadLenght; blockID++ {
-		go func(blockID int) {
-			defer wg.Done()
-
-			//creating a packet for each bloc that will contain the splits of this block
-			packet := []block{
-				blocks[blockID],
-			}
-
-			for layerID := 0; layerID < len(filters); layerID++ {
-				for filterID := 0; filterID < len(filters[layerID]); filterID++ {
-					for extractID := 0; extractID < len(packet); extractID++ {
-						if packet[extractID].slicedAlready {
-							continue
-						}
-						//in each layer
-						cut := filterCutBlock(packet[extractID], filters[layerID][filterID])
-
-						if cut[0].start != -1 {
-
-							packet[extractID].start = filterConversion(
-								cut[0].start,
-								filters[layerID][filterID].start,
-								filters[layerID][filterID].newBase)
-
-							packet[extractID].end = filterConversion(
-								cut[0].end,
-								filters[layerID][filterID].start,
-								filters[layerID][filterID].newBase)
-
-							packet[extractID].slicedAlready = true
-
-							if cut[1].start != -1 {
-								packet = append(packet, cut[1])
-							}
-
-							if cut[2].start != -1 {
-								packet = append(packet, cut[2])
-							}
-
-						}
-					}
-				}
-
-				//regroup data to avoid slices getting to big layer after layer
-				packet = checkForMerge(packet)
-				for packetID := 0; packetID < len(packet); packetID++ {
-					packet[packetID].slicedAlready = false
-				}
-			}
-
-			// check for min for this final packet and push it to the output
-			packetMin := math.MaxInt
-			for packetID := 0; packetID < len(packet); packetID++ {
-				if packet[packetID].start < packetMin {
-					packetMin = packet[packetID].start
-				}
-			}
-			packetOutput = append(packetOutput, packetMin)
+// append a segment, merging it with the last one when they continue each other
+func (m *IntervalMap) add(s segment) {
+	if n := len(m.segments); n > 0 && m.segments[n-1].offset == s.offset && m.segments[n-1].end+1 == s.start {
+		m.segments[n-1].end = s.end
+		return
+	}
+	m.segments = append(m.segments, s)
+}
 
-		}(blockID)
+// segments overlapping the values lo to hi, the values out of the domain
+// are given as identity segments
+func (m IntervalMap) overlapping(lo, hi int) []segment {
+	out := []segment{}
+	if lo < domainMin {
+		out = append(out, segment{lo, min(hi, domainMin-1), 0})
 	}
+	i := sort.Search(len(m.segments), func(i int) bool { return m.segments[i].end >= lo })
+	for ; i < len(m.segments) && m.segments[i].start <= hi; i++ {
+		out = append(out, m.segments[i])
+	}
+	if hi > domainMax {
+		out = append(out, segment{max(lo, domainMax+1), hi, 0})
+	}
+	return out
+}
 
-	wg.Wait()
+// Map returns the image of x
+func (m IntervalMap) Map(x int) int {
+	for _, s := range m.overlapping(x, x) {
+		return x + s.offset
+	}
+	return x
+}
 
-	//check for real min after
-	min := math.MaxInt
-	for _, output := range packetOutput {
-		if output < min {
-			min = output
+// Inverse returns every x such that Map(x) is y, in increasing order
+func (m IntervalMap) Inverse(y int) []int {
+	out := []int{}
+	for _, s := range m.segments {
+		if x := y - s.offset; x >= s.start && x <= s.end {
+			out = append(out, x)
 		}
 	}
-	return min
+	sort.Ints(out)
+	return out
+}
+
+// MinOver returns the lowest image of the values from start to end (inclusive),
+// the lowest image of a segment is the image of its first value
+func (m IntervalMap) MinOver(start, end int) int {
+	result := math.MaxInt
+	for _, s := range m.overlapping(start, end) {
+		result = min(result, max(start, s.start)+s.offset)
+	}
+	return result
 }
@@ -0,0 +1,179 @@
+package Day5
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const example = `seeds: 79 14 55 13
+
+seed-to-soil map:
+50 98 2
+52 50 48
+
+soil-to-fertilizer map:
+0 15 37
+37 52 2
+39 0 15
+
+fertilizer-to-water map:
+49 53 8
+0 11 42
+42 0 7
+57 7 4
+
+water-to-light map:
+88 18 7
+18 25 70
+
+light-to-temperature map:
+45 77 23
+81 45 19
+68 64 13
+
+temperature-to-humidity map:
+0 69 1
+1 0 69
+
+humidity-to-location map:
+60 56 37
+56 93 4`
+
+func parseExample(t *testing.T) Input {
+	t.Helper()
+	almanac, err := Parse(strings.NewReader(example))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return almanac
+}
+
+func TestParts(t *testing.T) {
+	input := parseExample(t)
+
+	result, err := Part1(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Part1: %v", err)
+	}
+	if result.String() != "35" {
+		t.Errorf("Part1 = %s, expected 35", result)
+	}
+
+	result, err = Part2(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Part2: %v", err)
+	}
+	if result.String() != "46" {
+		t.Errorf("Part2 = %s, expected 46", result)
+	}
+}
+
+func TestAlmanacExample(t *testing.T) {
+	almanac, err := parseExample(t).Almanac()
+	if err != nil {
+		t.Fatalf("Almanac: %v", err)
+	}
+
+	//locations of the seeds given by the puzzle
+	for seed, location := range map[int]int{79: 82, 14: 43, 55: 86, 13: 35} {
+		if got := almanac.Map(seed); got != location {
+			t.Errorf("Map(%d) = %d, expected %d", seed, got, location)
+		}
+		found := false
+		for _, x := range almanac.Inverse(location) {
+			found = found || x == seed
+		}
+		if !found {
+			t.Errorf("Inverse(%d) = %v, expected it to contain %d", location, almanac.Inverse(location), seed)
+		}
+	}
+
+	//lowest location of each seed range, 46 is the location of seed 82
+	minimums := []struct{ start, end, expected int }{
+		{79, 92, 46}, {55, 67, 56},
+	}
+	for _, c := range minimums {
+		if got := almanac.MinOver(c.start, c.end); got != c.expected {
+			t.Errorf("MinOver(%d, %d) = %d, expected %d", c.start, c.end, got, c.expected)
+		}
+	}
+}
+
+// two layers whose filters meet at their edges:
+// 0-9 -> 100-109 and 10-19 -> 0-9, then 100-104 -> 10-14 and 105-109 -> 200-204.
+// Once composed 0-4 -> 10-14, 5-9 -> 200-204, 10-19 -> 0-9, 100-104 -> 10-14,
+// 105-109 -> 200-204 and the rest is unchanged
+var edgeInput = Input{filters: [][]blockFilter{
+	{{start: 0, end: 9, newBase: 100}, {start: 10, end: 19, newBase: 0}},
+	{{start: 100, end: 104, newBase: 10}, {start: 105, end: 109, newBase: 200}},
+}}
+
+func TestAlmanacEdges(t *testing.T) {
+	almanac, err := edgeInput.Almanac()
+	if err != nil {
+		t.Fatalf("Almanac: %v", err)
+	}
+
+	maps := []struct{ x, expected int }{
+		{-1, -1}, {0, 10}, {4, 14}, {5, 200}, {9, 204}, {10, 0}, {19, 9}, {20, 20},
+	}
+	for _, c := range maps {
+		if got := almanac.Map(c.x); got != c.expected {
+			t.Errorf("Map(%d) = %d, expected %d", c.x, got, c.expected)
+		}
+	}
+
+	inverses := []struct {
+		y        int
+		expected []int
+	}{
+		{0, []int{10}}, {9, []int{19}}, {10, []int{0, 100}}, {14, []int{4, 104}}, {20, []int{20}}, {200, []int{5, 105, 200}}, {-3, []int{-3}},
+	}
+	for _, c := range inverses {
+		if got := almanac.Inverse(c.y); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("Inverse(%d) = %v, expected %v", c.y, got, c.expected)
+		}
+	}
+
+	minimums := []struct{ start, end, expected int }{
+		{0, 4, 10}, {4, 5, 14}, {5, 9, 200}, {9, 10, 0}, {5, 25, 0}, {20, 25, 20}, {-5, 3, -5},
+	}
+	for _, c := range minimums {
+		if got := almanac.MinOver(c.start, c.end); got != c.expected {
+			t.Errorf("MinOver(%d, %d) = %d, expected %d", c.start, c.end, got, c.expected)
+		}
+	}
+}
+
+// the composed map gives the same values as the layers applied one after the other
+func TestAlmanacMatchesLayers(t *testing.T) {
+	for name, input := range map[string]Input{"example": parseExample(t), "edges": edgeInput} {
+		almanac, err := input.Almanac()
+		if err != nil {
+			t.Fatalf("%s: Almanac: %v", name, err)
+		}
+		for x := -10; x <= 120; x++ {
+			expected := x
+			for _, layer := range input.filters {
+				for _, f := range layer {
+					if expected >= f.start && expected <= f.end {
+						expected += f.newBase - f.start
+						break
+					}
+				}
+			}
+			if got := almanac.Map(x); got != expected {
+				t.Errorf("%s: Map(%d) = %d, expected %d", name, x, got, expected)
+			}
+		}
+	}
+}
+
+func TestOverlappingFilters(t *testing.T) {
+	input := Input{filters: [][]blockFilter{{{start: 0, end: 10, newBase: 50}, {start: 10, end: 12, newBase: 80}}}}
+	if _, err := input.Almanac(); err == nil {
+		t.Errorf("Almanac of overlapping filters, expected an error")
+	}
+}
@@ -110,40 +110,37 @@ How many ways can you beat the record in this one much longer race?
 package Day6
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"io"
 	"strconv"
 	"strings"
 )
 
-func Day6() [2]int {
-	return [2]int{
-		d6p1(),
-		d6p2(),
-	}
+func init() {
+	solver.Register(6, solver.New(Parse, Part1, Part2))
 }
 
-func d6p1() int {
-	//step 1 open and scan the file
-	file, err := os.Open("./Day6/Ressources/day6_input.txt")
+// Input is the sheet of paper, race durations and the matching
+// record distances to beat
+type Input struct {
+	raceDurations   []int
+	distancesTobeat []int
+}
 
-	if err != nil {
-		log.Fatal(err)
+// Parse reads the Time: and Distance: lines of the sheet
+func Parse(r io.Reader) (Input, error) {
+	input := Input{
+		raceDurations:   []int{},
+		distancesTobeat: []int{},
 	}
 
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	raceDurations := []int{}
-	distancesTobeat := []int{}
-
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
 
 	for scanner.Scan() {
+		lineNumber++
 		//extract the time values first
 		splitTimes := strings.Split(scanner.Text(), "Time:")
 
@@ -154,9 +151,9 @@ func d6p1() int {
 				if splitTimes[i] != "" {
 					v, err := strconv.Atoi(splitTimes[i])
 					if err != nil {
-						log.Fatal(err)
+						return Input{}, solver.NewParseError(lineNumber, solver.Column(scanner.Text(), splitTimes[i]), err)
 					}
-					raceDurations = append(raceDurations, v)
+					input.raceDurations = append(input.raceDurations, v)
 				}
 			}
 		}
@@ -171,24 +168,39 @@ func d6p1() int {
 				if splitDistance[i] != "" {
 					v, err := strconv.Atoi(splitDistance[i])
 					if err != nil {
-						log.Fatal(err)
+						return Input{}, solver.NewParseError(lineNumber, solver.Column(scanner.Text(), splitDistance[i]), err)
 					}
-					distancesTobeat = append(distancesTobeat, v)
+					input.distancesTobeat = append(input.distancesTobeat, v)
 				}
 			}
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return Input{}, err
+	}
+
+	//every race needs its record distance
+	if len(input.raceDurations) != len(input.distancesTobeat) {
+		return Input{}, solver.Errorf(lineNumber, 0, "found %d race durations but %d distances", len(input.raceDurations), len(input.distancesTobeat))
 	}
 
+	return input, nil
+}
+
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	raceDurations := input.raceDurations
+	distancesTobeat := input.distancesTobeat
+
 	// speed  += time pressed
 	// time = max duration of a race
 	// distance = record to beat
 
 	errorMargin := 0
 	for i, duration := range raceDurations {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
+		}
 		winPossibilityCount := 0
 		for pressDuration := 0; pressDuration < duration; pressDuration++ {
 			distance := pressDuration * (duration - pressDuration)
@@ -203,69 +215,13 @@ func d6p1() int {
 		}
 	}
 
-	return errorMargin
+	return solver.Int(errorMargin), nil
 }
 
-func d6p2() int {
-	//first open and scan the file
-	file, err := os.Open("./Day6/Ressources/day6_input.txt")
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	raceDuration := 0
-	distanceTobeat := 0
-
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		//first find all the times and concatenate them by stripping the spaces
-		splitTimes := strings.Split(scanner.Text(), "Time:")
-
-		if len(splitTimes) == 2 {
-			splitTimes = strings.Split(splitTimes[1], " ")
-			mergedTime := ""
-			for i := 0; i < len(splitTimes); i++ {
-				if splitTimes[i] != "" {
-					mergedTime += splitTimes[i]
-				}
-			}
-			v, err := strconv.Atoi(mergedTime)
-			if err != nil {
-				log.Fatal(err)
-			}
-			raceDuration = v
-		}
-
-		//Second find all the distance and concatenate them by stripping the spaces
-		splitDistance := strings.Split(scanner.Text(), "Distance:")
-
-		if len(splitDistance) == 2 {
-			splitDistance = strings.Split(splitDistance[1], " ")
-			mergedDistance := ""
-			for i := 0; i < len(splitDistance); i++ {
-				if splitDistance[i] != "" {
-					mergedDistance += splitDistance[i]
-				}
-			}
-			v, err := strconv.Atoi(mergedDistance)
-			if err != nil {
-				log.Fatal(err)
-			}
-			distanceTobeat = v
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	//the kerning was bad, all the numbers of a line are a single number
+	raceDuration := mergeNumbers(input.raceDurations)
+	distanceTobeat := mergeNumbers(input.distancesTobeat)
 
 	// speed  += time pressed
 	// time = max duration of a race
@@ -273,11 +229,32 @@ func d6p2() int {
 
 	winWaysCount := 0
 	for pressDuration := 0; pressDuration < raceDuration; pressDuration++ {
+		//the loop is too tight to check the context on every press
+		if pressDuration%(1<<20) == 0 {
+			if err := ctx.Err(); err != nil {
+				return solver.Result{}, err
+			}
+		}
 		distance := pressDuration * (raceDuration - pressDuration)
 		if distance > distanceTobeat {
 			winWaysCount++
 		}
 	}
 
-	return winWaysCount
+	return solver.Int(winWaysCount), nil
+}
+
+// concatenate the digits of all the numbers, ex: [7 15 30] => 71530
+func mergeNumbers(numbers []int) int {
+	merged := 0
+	for _, n := range numbers {
+		for shift := n; shift > 0; shift /= 10 {
+			merged *= 10
+		}
+		if n == 0 {
+			merged *= 10
+		}
+		merged += n
+	}
+	return merged
 }
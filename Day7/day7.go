@@ -126,315 +126,298 @@ What are the new total winnings?
 package Day7
 
 import (
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"fmt"
+	"io"
+	"sort"
 	"strconv"
 	"strings"
 )
 
-type hand struct {
-	cards     string
-	bid       int
-	frequency map[rune]int
-	score     int
-}
-
-var cardScore1 map[rune]int = map[rune]int{
-	'2': 2,
-	'3': 3,
-	'4': 4,
-	'5': 5,
-	'6': 6,
-	'7': 7,
-	'8': 8,
-	'9': 9,
-	'T': 10,
-	'J': 11,
-	'Q': 12,
-	'K': 13,
-	'A': 14,
+func init() {
+	solver.Register(7, solver.New(Parse, Part1, Part2))
 }
 
-var cardScore2 map[rune]int = map[rune]int{
-	'J': 1,
-	'2': 2,
-	'3': 3,
-	'4': 4,
-	'5': 5,
-	'6': 6,
-	'7': 7,
-	'8': 8,
-	'9': 9,
-	'T': 10,
-	'Q': 12,
-	'K': 13,
-	'A': 14,
+type hand struct {
+	cards string
+	bid   int
 }
 
-const FIVE_OF_A_KIND string = "Five of a kind"
-const FOUR_OF_A_KIND string = "Four of a kind"
-const FULL_HOUSE string = "Full house"
-const THREE_OF_A_KIND string = "Three of a kind"
-const TWO_PAIR string = "Two pair"
-const ONE_PAIR string = "One pair"
-const HIGH_CARD string = "High card"
-
-var comboScore map[string]int = map[string]int{
-	FIVE_OF_A_KIND:  7,
-	FOUR_OF_A_KIND:  6,
-	FULL_HOUSE:      5,
-	THREE_OF_A_KIND: 4,
-	TWO_PAIR:        3,
-	ONE_PAIR:        2,
-	HIGH_CARD:       1,
+// Category is a kind of hand, a hand belongs to the strongest category it matches.
+// Matches gets the sizes of the groups of equal cards, largest first, once the
+// wildcards are used
+type Category struct {
+	Name    string
+	Matches func(groups []int) bool
 }
 
-func Day7() [2]int {
-	return [2]int{
-		d7p1(),
-		d7p2(),
-	}
+// RuleSet is a variant of Camel Cards
+type RuleSet struct {
+	Name string
+	//every card from the weakest to the strongest
+	CardOrder string
+	//cards that act like whatever card makes the hand the strongest
+	Wildcards string
+	//categories from the strongest to the weakest, the last one should match any hand
+	Categories []Category
+	//compare the strengths of the cards of two hands of the same category,
+	//negative when a is weaker than b, InOrder is used when nil
+	TieBreak func(a, b []int) int
 }
 
-// walk through the cards and check for repetition,
-// frequency of a card is equal to number of repetition of such card
-func getCharFrequency(cards string) map[rune]int {
-	var output map[rune]int = map[rune]int{}
-
-	for _, r := range cards {
-		_, ok := output[r]
-		if ok {
-			output[r]++
-		} else {
-			output[r] = 1
+// hasGroups matches the hands with groups at least as large as sizes, largest first
+func hasGroups(sizes ...int) func(groups []int) bool {
+	return func(groups []int) bool {
+		if len(groups) < len(sizes) {
+			return false
 		}
+		for i, size := range sizes {
+			if groups[i] < size {
+				return false
+			}
+		}
+		return true
 	}
-
-	return output
 }
 
-// calculate the combo score of a set of card based on the card frequency
-func getComboScoreFromHand(frequency map[rune]int, useWildCard bool) int {
-	jFreq, ok := frequency['J']
-	wildCardInitialized := false
+// the categories of the puzzle
+var standardCategories = []Category{
+	{Name: "Five of a kind", Matches: hasGroups(5)},
+	{Name: "Four of a kind", Matches: hasGroups(4)},
+	{Name: "Full house", Matches: hasGroups(3, 2)},
+	{Name: "Three of a kind", Matches: hasGroups(3)},
+	{Name: "Two pair", Matches: hasGroups(2, 2)},
+	{Name: "One pair", Matches: hasGroups(2)},
+	{Name: "High card", Matches: hasGroups()},
+}
 
-	//in case of wild card we convert it to the higest value cart type
-	if useWildCard && ok {
+// Standard is the rule set of part 1
+var Standard = RuleSet{
+	Name:       "standard",
+	CardOrder:  "23456789TJQKA",
+	Categories: standardCategories,
+}
 
-		var rHighestFreq rune
-		max := 0
+// Jokers is the rule set of part 2, J is the weakest card but it is a wildcard
+var Jokers = RuleSet{
+	Name:       "jokers",
+	CardOrder:  "J23456789TQKA",
+	Wildcards:  "J",
+	Categories: standardCategories,
+}
 
-		if jFreq == 5 {
-			return comboScore[FIVE_OF_A_KIND]
+// InOrder compares the cards one by one in the order of the hand,
+// the first different card decides
+func InOrder(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
 		}
+	}
+	return len(a) - len(b)
+}
 
-		for r, i := range frequency {
-			if r == 'J' {
-				continue
-			}
-			if i > max {
-				max = i
-				rHighestFreq = r
-			}
-		}
+// Group is a set of equal cards of a hand, Wildcards of them are wildcards
+// used as Card
+type Group struct {
+	Card      rune
+	Size      int
+	Wildcards int
+}
 
-		frequency[rHighestFreq] += jFreq
-		delete(frequency, 'J')
-		wildCardInitialized = true
-	}
+// Classification tells the category of a hand and why it got it
+type Classification struct {
+	Cards string
+	//index of the category in the rule set, 0 is the strongest
+	Rank     int
+	Category string
+	Groups   []Group
+	//strength of each card in the order of the hand, used for the tie-break
+	Strengths []int
+}
 
-	//once wildcard check are passed we calculate score normally
-	if !useWildCard || !ok || wildCardInitialized {
-		tempCombo := 0
-		for _, f := range frequency {
-			if f == 5 {
-				return comboScore[FIVE_OF_A_KIND]
-			} else if f == 4 {
-				return comboScore[FOUR_OF_A_KIND]
-			} else if f == 3 {
-				if tempCombo == 0 {
-					tempCombo = comboScore[THREE_OF_A_KIND]
-				} else if tempCombo == comboScore[ONE_PAIR] {
-					return comboScore[FULL_HOUSE]
-				}
-			} else if f == 2 {
-				if tempCombo == 0 {
-					tempCombo = comboScore[ONE_PAIR]
-				} else if tempCombo == comboScore[THREE_OF_A_KIND] {
-					return comboScore[FULL_HOUSE]
-				} else if tempCombo == comboScore[ONE_PAIR] {
-					return comboScore[TWO_PAIR]
-				}
-			}
-		}
-		if tempCombo == 0 {
-			return comboScore[HIGH_CARD]
-		} else {
-			return tempCombo
+// Reason explains the category, ex: "KTJJT is Four of a kind: 4 x T (2 wildcards as T), 1 x K"
+func (c Classification) Reason() string {
+	parts := make([]string, len(c.Groups))
+	for i, g := range c.Groups {
+		parts[i] = fmt.Sprintf("%d x %c", g.Size, g.Card)
+		if g.Wildcards > 0 && g.Wildcards < g.Size {
+			parts[i] += fmt.Sprintf(" (%d wildcards as %c)", g.Wildcards, g.Card)
 		}
 	}
-	return -1
+	return fmt.Sprintf("%s is %s: %s", c.Cards, c.Category, strings.Join(parts, ", "))
 }
 
-// compare 2 hands and see which one has the best score (-1 : a > b) (0 : a==B) (1 a < b)
-func compareHands(a hand, b hand, useWildCard bool) int {
-	//TODO compare a to be to see who is higher
-	scoreTable := cardScore1
-
-	if useWildCard {
-		scoreTable = cardScore2
+// Classify finds the category of a hand, the wildcards join the largest group
+// of the other cards which always gives the strongest category
+func (rs RuleSet) Classify(cards string) (Classification, error) {
+	c := Classification{Cards: cards, Strengths: make([]int, 0, len(cards))}
+
+	counts := map[rune]int{}
+	order := []rune{}
+	wildcards := 0
+	var wildcard rune
+	for i, r := range cards {
+		strength := strings.IndexRune(rs.CardOrder, r)
+		if strength == -1 {
+			return Classification{}, fmt.Errorf("unknown card %q at position %d of hand %q for rules %q", r, i+1, cards, rs.Name)
+		}
+		c.Strengths = append(c.Strengths, strength)
 
+		if strings.ContainsRune(rs.Wildcards, r) {
+			wildcards++
+			wildcard = r
+			continue
+		}
+		if counts[r] == 0 {
+			order = append(order, r)
+		}
+		counts[r]++
 	}
 
-	if a.score < b.score {
-		return 1
-	} else if a.score > b.score {
-		return -1
-	} else {
-		bRunes := []rune(b.cards)
-		for i, aRune := range a.cards {
-			if aRune == bRunes[i] {
-				continue
-			}
+	//largest groups first, the strongest card first for the groups of the same size
+	for _, r := range order {
+		c.Groups = append(c.Groups, Group{Card: r, Size: counts[r]})
+	}
+	sort.SliceStable(c.Groups, func(i, j int) bool {
+		if c.Groups[i].Size != c.Groups[j].Size {
+			return c.Groups[i].Size > c.Groups[j].Size
+		}
+		return strings.IndexRune(rs.CardOrder, c.Groups[i].Card) > strings.IndexRune(rs.CardOrder, c.Groups[j].Card)
+	})
 
-			if scoreTable[aRune] < scoreTable[bRunes[i]] {
-				return 1
-			} else {
-				return -1
-			}
+	if wildcards > 0 {
+		if len(c.Groups) == 0 {
+			c.Groups = []Group{{Card: wildcard}}
 		}
-		return 0
+		c.Groups[0].Size += wildcards
+		c.Groups[0].Wildcards = wildcards
 	}
-}
 
-// buble sort the array of hand based on their score
-func bubbleSort(arr []hand, useWildeCard bool) {
-	n := len(arr)
-	for i := 0; i < n-1; i++ {
-		swapped := false
-		for j := 0; j < n-i-1; j++ {
-			if compareHands(arr[j], arr[j+1], useWildeCard) == -1 {
-				arr[j], arr[j+1] = arr[j+1], arr[j]
-				swapped = true
-			}
-		}
-		if !swapped {
-			break
+	sizes := make([]int, len(c.Groups))
+	for i, g := range c.Groups {
+		sizes[i] = g.Size
+	}
+	for rank, category := range rs.Categories {
+		if category.Matches(sizes) {
+			c.Rank, c.Category = rank, category.Name
+			return c, nil
 		}
 	}
+	return Classification{}, fmt.Errorf("hand %q matches no category of rules %q", cards, rs.Name)
 }
 
-func d7p1() int {
-
-	//step 1 load the file and scan it to extract data
-	file, err := os.Open("./Day7/Ressources/day7_input.txt")
-	if err != nil {
-		log.Fatal(err)
+// Less tells if the hand a is weaker than the hand b
+func (rs RuleSet) Less(a, b Classification) bool {
+	if a.Rank != b.Rank {
+		return a.Rank > b.Rank
+	}
+	tieBreak := rs.TieBreak
+	if tieBreak == nil {
+		tieBreak = InOrder
 	}
+	return tieBreak(a.Strengths, b.Strengths) < 0
+}
 
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+// Input is the list of hands with their bid, the category of each hand
+// is found by the parts since it depends on the rule set
+type Input []hand
 
-	scanner := bufio.NewScanner(file)
+// Parse reads each hand of cards and its bid
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
 
-	data := []hand{}
+	data := Input{}
+	lineNumber := 0
 
 	for scanner.Scan() {
+		lineNumber++
 		line := strings.Split(scanner.Text(), " ")
+		if len(line) != 2 {
+			return nil, solver.Errorf(lineNumber, 0, "expected a hand and a bid in line: %q", scanner.Text())
+		}
 
 		b, err := strconv.Atoi(line[1])
 
 		if err != nil {
-			log.Fatal(err)
+			return nil, solver.NewParseError(lineNumber, len(line[0])+2, err)
 		}
 
-		//get all the necessay info from the extracted hand of card
-		f := getCharFrequency(line[0])
-		s := getComboScoreFromHand(f, false)
-
-		h := hand{
-			cards:     line[0],
-			bid:       b,
-			frequency: f,
-			score:     s,
-		}
-
-		data = append(data, h)
+		data = append(data, hand{
+			cards: line[0],
+			bid:   b,
+		})
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
-	//step 2 sort the array based on score
-	bubbleSort(data, false)
-
-	//step 3 calculate result
-	sumProd := 0
-	for i := 1; i <= len(data); i++ {
-		sumProd += i * data[i-1].bid
+		return nil, err
 	}
 
-	return sumProd
+	return data, nil
 }
 
-func d7p2() int {
-
-	//step 1 load the file and scan it to extract data
-	file, err := os.Open("./Day7/Ressources/day7_input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
+// classify every hand then sort them from the weakest to the strongest,
+// the hands that tie keep the order of the input
+func rankHands(ctx context.Context, input Input, rules RuleSet) ([]Classification, []int, error) {
+	classes := make([]Classification, len(input))
+	bids := make([]int, len(input))
+	for i, h := range input {
+		if i%(1<<12) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
 		}
-	}()
-
-	scanner := bufio.NewScanner(file)
-
-	data := []hand{}
-
-	for scanner.Scan() {
-		line := strings.Split(scanner.Text(), " ")
-
-		b, err := strconv.Atoi(line[1])
-
+		c, err := rules.Classify(h.cards)
 		if err != nil {
-			log.Fatal(err)
-		}
-
-		//get all the necessay info from the extracted hand of card
-		f := getCharFrequency(line[0])
-		s := getComboScoreFromHand(f, true)
-
-		h := hand{
-			cards:     line[0],
-			bid:       b,
-			frequency: f,
-			score:     s,
+			return nil, nil, fmt.Errorf("hand %d: %w", i+1, err)
 		}
-
-		data = append(data, h)
+		classes[i], bids[i] = c, h.bid
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+	order := make([]int, len(input))
+	for i := range order {
+		order[i] = i
 	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rules.Less(classes[order[i]], classes[order[j]])
+	})
+
+	sortedClasses := make([]Classification, len(order))
+	sortedBids := make([]int, len(order))
+	for i, id := range order {
+		sortedClasses[i], sortedBids[i] = classes[id], bids[id]
+	}
+	return sortedClasses, sortedBids, nil
+}
 
-	//step 2 sort the array based on score
-	bubbleSort(data, true)
+// sum the bid of every hand times its rank
+func totalWinnings(ctx context.Context, input Input, rules RuleSet) (int, error) {
+	_, bids, err := rankHands(ctx, input, rules)
+	if err != nil {
+		return 0, err
+	}
 
-	//step 3 calculate result
 	sumProd := 0
-	for i := 1; i <= len(data); i++ {
-		sumProd += i * data[i-1].bid
+	for i, bid := range bids {
+		sumProd += (i + 1) * bid
 	}
+	return sumProd, nil
+}
 
-	return sumProd
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	total, err := totalWinnings(ctx, input, Standard)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(total), nil
+}
+
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
+	total, err := totalWinnings(ctx, input, Jokers)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(total), nil
 }
@@ -91,20 +91,19 @@ package Day8
 
 import (
 	utils "AdventOfCode/Utils"
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"regexp"
+	"sort"
 	"sync"
 )
 
-func Day8() [2]int {
-	result := [2]int{
-		d8p1(),
-		d8p2(),
-	}
-	return result
-
+func init() {
+	solver.Register(8, solver.New(Parse, Part1, Part2))
 }
 
 type node struct {
@@ -114,36 +113,41 @@ type node struct {
 	right    string
 }
 
-func createTree(path string) ([]rune, map[string]node) {
-	//step 1 open the file and scan it
-	file, err := os.Open(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
+// Input is the documents: the left/right instructions and the network of nodes
+type Input struct {
+	instructions []rune
+	tree         map[string]node
+}
 
+// Parse reads the instructions and create the tree of nodes
+func Parse(input io.Reader) (Input, error) {
 	instructions := []rune{}
 	tree := map[string]node{}
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(input)
 
 	instructionFinihed := false
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		if !instructionFinihed {
 			if scanner.Text() == "" {
 				instructionFinihed = true
 			} else {
+				for i, r := range scanner.Text() {
+					if r != 'L' && r != 'R' {
+						return Input{}, solver.Errorf(lineNumber, i+1, "unknown instruction %q, expected L or R", r)
+					}
+				}
 				instructions = append(instructions, []rune(scanner.Text())...)
 			}
 		} else {
 
 			re := regexp.MustCompile(`\b[A-Za-z0-9]{3}\b`)
 			names := re.FindAllString(scanner.Text(), -1)
+			if len(names) != 3 {
+				return Input{}, solver.Errorf(lineNumber, 0, "expected 3 node names in line: %q", scanner.Text())
+			}
 
 			n := node{
 				name:     names[0],
@@ -157,79 +161,225 @@ func createTree(path string) ([]rune, map[string]node) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return Input{}, err
 	}
 
-	return instructions, tree
+	return Input{instructions: instructions, tree: tree}, nil
 }
 
-func walkWithPatternUntilEqual(tree map[string]node, instructions []rune, a string, b string, useLast bool) int {
-	stepCount := 0
-	start := a
-	end := b
-	current := start
+// Cycle describes the walk of one ghost over the (node, instruction index) states.
+// The walk is a tail of Tail steps followed by a loop of Length steps that repeats forever,
+// TailHits are the steps of the tail ending on an end node and Offsets are the steps
+// of the first loop ending on an end node (Tail <= offset < Tail+Length)
+type Cycle struct {
+	Start    string
+	Tail     int
+	Length   int
+	TailHits []int
+	Offsets  []int
+}
 
-	for {
-		for i := 0; i < len(instructions); i++ {
-			if instructions[i] == 'L' {
-				current = tree[current].left
-			} else {
-				current = tree[current].right
+// maximum number of combinations of offsets tried with the CRT
+const maxCombinations = 1 << 16
+
+// AnalyzeWalk follows the instructions from start until a (node, instruction index)
+// state repeats, isEnd tells which nodes are an end of the walk
+func AnalyzeWalk(ctx context.Context, input Input, start string, isEnd func(string) bool) (Cycle, error) {
+	instructions, tree := input.instructions, input.tree
+	if len(instructions) == 0 {
+		return Cycle{}, errors.New("no instructions to follow")
+	}
+	if _, ok := tree[start]; !ok {
+		return Cycle{}, fmt.Errorf("unknown start node %q", start)
+	}
+
+	type state struct {
+		node string
+		idx  int
+	}
+
+	//step at which each state is seen first, and the node of every step
+	seen := map[state]int{}
+	path := []string{}
+
+	current := start
+	for step := 0; ; step++ {
+		if step%(1<<12) == 0 {
+			if err := ctx.Err(); err != nil {
+				return Cycle{}, err
 			}
-			stepCount++
+		}
 
-			if useLast {
-				if tree[current].lastChar == end {
-					return stepCount
+		s := state{node: current, idx: step % len(instructions)}
+		if first, ok := seen[s]; ok {
+			c := Cycle{Start: start, Tail: first, Length: step - first}
+			for t, name := range path {
+				if !isEnd(name) {
+					continue
 				}
-			} else {
-				if current == end {
-					return stepCount
+				if t < first {
+					c.TailHits = append(c.TailHits, t)
+				} else {
+					c.Offsets = append(c.Offsets, t)
 				}
 			}
+			return c, nil
+		}
+		seen[s] = step
+		path = append(path, current)
 
+		n, ok := tree[current]
+		if !ok {
+			return Cycle{}, fmt.Errorf("unknown node %q reached after %d steps from %q", current, step, start)
+		}
+		if instructions[s.idx] == 'L' {
+			current = n.left
+		} else {
+			current = n.right
 		}
 	}
 }
 
-func d8p1() int {
-	instructions, tree := createTree("./Day8/Ressources/day8_input.txt")
-	return walkWithPatternUntilEqual(tree, instructions, "AAA", "ZZZ", false)
+// At tells if the walk is on an end node after the given number of steps
+func (c Cycle) At(step int) bool {
+	if step < c.Tail {
+		i := sort.SearchInts(c.TailHits, step)
+		return i < len(c.TailHits) && c.TailHits[i] == step
+	}
+	step = c.Tail + (step-c.Tail)%c.Length
+	i := sort.SearchInts(c.Offsets, step)
+	return i < len(c.Offsets) && c.Offsets[i] == step
 }
 
-func d8p2() int {
-	instructions, tree := createTree("./Day8/Ressources/day8_input.txt")
-	startingNodes := []string{}
-	pathsStepCount := []int{}
+// FirstMeeting returns the first step at which every walk is on an end node.
+// The steps before the longest tail are checked one by one, after it every walk
+// is in its loop and each combination of offsets is solved with the CRT
+func FirstMeeting(ctx context.Context, cycles []Cycle) (int, error) {
+	if len(cycles) == 0 {
+		return 0, errors.New("no walk to follow")
+	}
+
+	tail := 0
+	combinations := 1
+	for _, c := range cycles {
+		tail = max(tail, c.Tail)
+		combinations *= max(len(c.Offsets), 1)
+		if combinations > maxCombinations {
+			return 0, fmt.Errorf("more than %d combinations of end nodes to check", maxCombinations)
+		}
+	}
+
+	for step := 0; step < tail; step++ {
+		if step%(1<<12) == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+		all := true
+		for _, c := range cycles {
+			if !c.At(step) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return step, nil
+		}
+	}
+
+	for _, c := range cycles {
+		if len(c.Offsets) == 0 {
+			return 0, fmt.Errorf("the walk from %q never reaches an end node after %d steps", c.Start, tail)
+		}
+	}
+
+	//pick one offset of each walk, the choice is given by the digits of n in the mixed radix of the offset counts
+	best := -1
+	residues := make([]int, len(cycles))
+	moduli := make([]int, len(cycles))
+	for n := 0; n < combinations; n++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		rest := n
+		for i, c := range cycles {
+			residues[i] = c.Offsets[rest%len(c.Offsets)]
+			moduli[i] = c.Length
+			rest /= len(c.Offsets)
+		}
+
+		x, lcm, err := utils.CRT(residues, moduli)
+		if err != nil {
+			//these offsets are never reached at the same time
+			continue
+		}
+		//first solution that every walk reaches inside its loop
+		if x < tail {
+			x += (tail - x + lcm - 1) / lcm * lcm
+		}
+		if best == -1 || x < best {
+			best = x
+		}
+	}
+
+	if best == -1 {
+		return 0, errors.New("the walks never reach end nodes at the same step")
+	}
+	return best, nil
+}
+
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
+	cycle, err := AnalyzeWalk(ctx, input, "AAA", func(name string) bool { return name == "ZZZ" })
+	if err != nil {
+		return solver.Result{}, err
+	}
+	steps, err := FirstMeeting(ctx, []Cycle{cycle})
+	if err != nil {
+		return solver.Result{}, err
+	}
+	return solver.Int(steps), nil
+}
 
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
 	startChar := "A"
 	endChar := "Z"
 
-	//find all starting
-	for k := range tree {
-		if tree[k].lastChar == startChar {
+	//find all starting, sorted to report the same walk first on every run
+	startingNodes := []string{}
+	for k := range input.tree {
+		if input.tree[k].lastChar == startChar {
 			startingNodes = append(startingNodes, k)
 		}
 	}
+	sort.Strings(startingNodes)
+
+	isEnd := func(name string) bool {
+		n, ok := input.tree[name]
+		return ok && n.lastChar == endChar
+	}
 
-	//get the step count to finding a Z of each starting node (multithreaded)
+	//analyze the walk of each starting node (multithreaded)
+	//each goroutine writes in its own slot
 	var wg sync.WaitGroup
 	wg.Add(len(startingNodes))
+	cycles := make([]Cycle, len(startingNodes))
+	cyclesErrors := make([]error, len(startingNodes))
 
 	for i := 0; i < len(startingNodes); i++ {
 		go func(i int) {
 			defer wg.Done()
-			n := walkWithPatternUntilEqual(tree, instructions, startingNodes[i], endChar, true)
-			pathsStepCount = append(pathsStepCount, n)
+			cycles[i], cyclesErrors[i] = AnalyzeWalk(ctx, input, startingNodes[i], isEnd)
 		}(i)
 	}
 	wg.Wait()
 
-	//calculates the Least Common Multiple (LCM) of a list of numbers
-	result := 1
-	for _, num := range pathsStepCount {
-		gcd := utils.LCM(result, num) // Calculate GCD of 'result' and 'num'
-		result = (result / gcd) * num // Update 'result'
+	if err := errors.Join(cyclesErrors...); err != nil {
+		return solver.Result{}, err
+	}
+
+	steps, err := FirstMeeting(ctx, cycles)
+	if err != nil {
+		return solver.Result{}, err
 	}
-	return result
+	return solver.Int(steps), nil
 }
@@ -0,0 +1,158 @@
+package Day8
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseExample(t *testing.T, input string) Input {
+	t.Helper()
+	documents, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return documents
+}
+
+func TestPart1(t *testing.T) {
+	examples := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "straight",
+			input: `RL
+
+AAA = (BBB, CCC)
+BBB = (DDD, EEE)
+CCC = (ZZZ, GGG)
+DDD = (DDD, DDD)
+EEE = (EEE, EEE)
+GGG = (GGG, GGG)
+ZZZ = (ZZZ, ZZZ)`,
+			expected: "2",
+		},
+		{
+			name: "repeated instructions",
+			input: `LLR
+
+AAA = (BBB, BBB)
+BBB = (AAA, ZZZ)
+ZZZ = (ZZZ, ZZZ)`,
+			expected: "6",
+		},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			result, err := Part1(context.Background(), parseExample(t, ex.input))
+			if err != nil {
+				t.Fatalf("Part1: %v", err)
+			}
+			if result.String() != ex.expected {
+				t.Errorf("Part1 = %s, expected %s", result, ex.expected)
+			}
+		})
+	}
+}
+
+func TestPart2(t *testing.T) {
+	examples := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "puzzle",
+			input: `LR
+
+11A = (11B, XXX)
+11B = (XXX, 11Z)
+11Z = (11B, XXX)
+22A = (22B, XXX)
+22B = (22C, 22C)
+22C = (22Z, 22Z)
+22Z = (22B, 22B)
+XXX = (XXX, XXX)`,
+			expected: "6",
+		},
+		{
+			//the end nodes are reached at 1, 4, 7... and at 3, 5, 7...
+			//the LCM of the first hits would give 3
+			name: "not aligned",
+			input: `L
+
+11A = (11Z, 11Z)
+11Z = (11B, 11B)
+11B = (11C, 11C)
+11C = (11Z, 11Z)
+22A = (22B, 22B)
+22B = (22C, 22C)
+22C = (22Z, 22Z)
+22Z = (22D, 22D)
+22D = (22Z, 22Z)`,
+			expected: "7",
+		},
+	}
+
+	for _, ex := range examples {
+		t.Run(ex.name, func(t *testing.T) {
+			result, err := Part2(context.Background(), parseExample(t, ex.input))
+			if err != nil {
+				t.Fatalf("Part2: %v", err)
+			}
+			if result.String() != ex.expected {
+				t.Errorf("Part2 = %s, expected %s", result, ex.expected)
+			}
+		})
+	}
+}
+
+func TestAnalyzeWalk(t *testing.T) {
+	input := parseExample(t, `L
+
+11A = (11Z, 11Z)
+11Z = (11B, 11B)
+11B = (11C, 11C)
+11C = (11Z, 11Z)
+22A = (22B, 22B)
+22B = (22C, 22C)
+22C = (22Z, 22Z)
+22Z = (22D, 22D)
+22D = (22Z, 22Z)`)
+	isEnd := func(name string) bool { return strings.HasSuffix(name, "Z") }
+
+	expected := []Cycle{
+		{Start: "11A", Tail: 1, Length: 3, Offsets: []int{1}},
+		{Start: "22A", Tail: 3, Length: 2, Offsets: []int{3}},
+	}
+	for _, want := range expected {
+		got, err := AnalyzeWalk(context.Background(), input, want.Start, isEnd)
+		if err != nil {
+			t.Fatalf("AnalyzeWalk(%s): %v", want.Start, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("AnalyzeWalk(%s) = %+v, expected %+v", want.Start, got, want)
+		}
+	}
+}
+
+// the end nodes are reached on odd steps by one walk and on even steps by the other
+func TestWalksNeverMeet(t *testing.T) {
+	input := parseExample(t, `L
+
+11A = (11Z, 11Z)
+11Z = (11B, 11B)
+11B = (11Z, 11Z)
+22A = (22B, 22B)
+22B = (22Z, 22Z)
+22Z = (22C, 22C)
+22C = (22Z, 22Z)`)
+
+	if result, err := Part2(context.Background(), input); err == nil {
+		t.Errorf("Part2 = %s, expected an error", result)
+	}
+}
@@ -141,17 +141,48 @@ package Day9
 
 import (
 	utils "AdventOfCode/Utils"
+	"AdventOfCode/Utils/solver"
 	"bufio"
-	"log"
-	"os"
+	"context"
+	"errors"
+	"io"
+	"strconv"
 	"strings"
 )
 
-func Day9() [2]int {
-	return [2]int{
-		d9p1(),
-		d9p2(),
+func init() {
+	solver.Register(9, solver.New(Parse, Part1, Part2))
+}
+
+// Input is the OASIS report, one history of values per line
+type Input [][]int
+
+// Parse reads each history of the report
+func Parse(r io.Reader) (Input, error) {
+	scanner := bufio.NewScanner(r)
+	histories := Input{}
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		row, err := utils.AtoiArray(strings.Split(scanner.Text(), " "))
+		if err != nil {
+			//point at the value that could not be converted
+			column := 0
+			var numErr *strconv.NumError
+			if errors.As(err, &numErr) {
+				column = solver.Column(scanner.Text(), numErr.Num)
+			}
+			return nil, solver.NewParseError(lineNumber, column, err)
+		}
+		histories = append(histories, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
+
+	return histories, nil
 }
 
 func HistoryDiff(row []int) []int {
@@ -163,28 +194,13 @@ func HistoryDiff(row []int) []int {
 	return diff
 }
 
-func d9p1() int {
-	//step 1 open the file and scan it
-	file, err := os.Open("./Day9/Ressources/day9_input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
+func Part1(ctx context.Context, input Input) (solver.Result, error) {
 	result := 0
 
-	for scanner.Scan() {
-		row, err := utils.AtoiArray(strings.Split(scanner.Text(), " "))
-		if err != nil {
-			log.Fatal(err)
+	for _, row := range input {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
 		}
-
 		rows := [][]int{row}
 		for i := 0; i < len(rows); i++ {
 			sumDiff := 0
@@ -218,33 +234,20 @@ func d9p1() int {
 
 	}
 
-	return result
+	return solver.Int(result), nil
 }
 
-func d9p2() int {
-	//step 1 open the file and scan it
-	file, err := os.Open("./Day9/Ressources/day9_input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer func() {
-		if err = file.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
+func Part2(ctx context.Context, input Input) (solver.Result, error) {
 	result := 0
 
-	for scanner.Scan() {
-		row, err := utils.AtoiArray(strings.Split(scanner.Text(), " "))
-		//inverse row
-		utils.ReverseArray(row)
-
-		if err != nil {
-			log.Fatal(err)
+	for _, history := range input {
+		if err := ctx.Err(); err != nil {
+			return solver.Result{}, err
 		}
+		//inverse a copy of the row
+		row := make([]int, len(history))
+		copy(row, history)
+		utils.ReverseArray(row)
 
 		rows := [][]int{row}
 		for i := 0; i < len(rows); i++ {
@@ -277,5 +280,5 @@ func d9p2() int {
 
 	}
 
-	return result
+	return solver.Int(result), nil
 }
@@ -0,0 +1,96 @@
+package solver
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// Kind tells what a Result holds
+type Kind int
+
+const (
+	KindUnsolved Kind = iota // the part has no answer yet
+	KindNumber               // the answer is an integer of any size
+	KindText                 // the answer is a string
+	KindError                // the part failed, the error is the reason
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindUnsolved:
+		return "unsolved"
+	case KindNumber:
+		return "number"
+	case KindText:
+		return "text"
+	case KindError:
+		return "error"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Result is the answer of one part.
+// The zero Result is unsolved, so a part that is not written yet returns Result{}
+type Result struct {
+	kind   Kind
+	number *big.Int
+	text   string
+	err    error
+}
+
+// Int is the answer of a part that fits in an int
+func Int(n int) Result {
+	return Result{kind: KindNumber, number: big.NewInt(int64(n))}
+}
+
+// Big is the answer of a part that can overflow an int, n is copied
+func Big(n *big.Int) Result {
+	return Result{kind: KindNumber, number: new(big.Int).Set(n)}
+}
+
+// Text is the answer of a part that is not a number
+func Text(s string) Result {
+	return Result{kind: KindText, text: s}
+}
+
+// Unsolved is the result of a part that does not find its answer yet
+func Unsolved() Result {
+	return Result{}
+}
+
+// Error is the result of a part that failed
+func Error(err error) Result {
+	return Result{kind: KindError, err: err}
+}
+
+// Kind tells what the result holds
+func (r Result) Kind() Kind {
+	return r.kind
+}
+
+// Number returns the answer of a KindNumber result, nil for the other kinds
+func (r Result) Number() *big.Int {
+	if r.number == nil {
+		return nil
+	}
+	return new(big.Int).Set(r.number)
+}
+
+// Err returns the error of a KindError result, nil for the other kinds
+func (r Result) Err() error {
+	return r.err
+}
+
+// String returns the answer as it is typed on the puzzle website,
+// empty for an unsolved part and the error message for a failed one
+func (r Result) String() string {
+	switch r.kind {
+	case KindNumber:
+		return r.number.String()
+	case KindText:
+		return r.text
+	case KindError:
+		return r.err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+// Package solver provides the Solver interface implemented by every day package.
+// Days join the registry from their init function so that the runner can list,
+// pick and run them by number without any source edit.
+package solver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Solver describe the two parts of a day puzzle.
+// Each part reads the puzzle input from the reader handed by the runner
+// and gives up with the context error once ctx is done.
+// A part that fails, parsing included, returns an Error result
+type Solver interface {
+	Part1(ctx context.Context, input io.Reader) Result
+	Part2(ctx context.Context, input io.Reader) Result
+}
+
+// ParseError reports a malformed puzzle input.
+// Line and Column start at 1, Column is 0 when the error is about the whole line
+type ParseError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// NewParseError wraps err with the position where it was found in the input
+func NewParseError(line, column int, err error) error {
+	return &ParseError{Line: line, Column: column, Err: err}
+}
+
+// Errorf builds a ParseError from a formatted message
+func Errorf(line, column int, format string, args ...any) error {
+	return NewParseError(line, column, fmt.Errorf(format, args...))
+}
+
+// Column returns the 1 based column of the first occurence of token in text,
+// 0 if the token is not found
+func Column(text, token string) int {
+	return strings.Index(text, token) + 1
+}
+
+// day glues the Parse function of a day package to its two parts
+type day[T any] struct {
+	parse func(io.Reader) (T, error)
+	part1 func(context.Context, T) (Result, error)
+	part2 func(context.Context, T) (Result, error)
+}
+
+// New builds a Solver from the Parse function of a day and its exported Part1 and Part2.
+// The Solver parses the input again for each part. A part must still leave its Input
+// unchanged, a caller can run both parts on the same parsed Input.
+// The error of the parse or of the part becomes an Error result
+func New[T any](parse func(io.Reader) (T, error), part1, part2 func(context.Context, T) (Result, error)) Solver {
+	return day[T]{parse: parse, part1: part1, part2: part2}
+}
+
+// Part1 parses the input and runs the first part on it
+func (d day[T]) Part1(ctx context.Context, input io.Reader) Result {
+	return run(ctx, input, d.parse, d.part1)
+}
+
+// Part2 parses the input and runs the second part on it
+func (d day[T]) Part2(ctx context.Context, input io.Reader) Result {
+	return run(ctx, input, d.parse, d.part2)
+}
+
+// parse the input and run a part on it, folding any error in the result
+func run[T any](ctx context.Context, input io.Reader, parse func(io.Reader) (T, error), part func(context.Context, T) (Result, error)) Result {
+	data, err := parse(input)
+	if err != nil {
+		return Error(err)
+	}
+	r, err := part(ctx, data)
+	if err != nil {
+		return Error(err)
+	}
+	return r
+}
+
+var registry = map[int]Solver{}
+
+// Register makes a solver available under its day number.
+// It panics if the same day is registered twice
+func Register(day int, s Solver) {
+	if s == nil {
+		panic(fmt.Sprintf("solver: Register day %d with a nil solver", day))
+	}
+	if _, dup := registry[day]; dup {
+		panic(fmt.Sprintf("solver: Register called twice for day %d", day))
+	}
+	registry[day] = s
+}
+
+// Get returns the solver registered for a day
+func Get(day int) (Solver, bool) {
+	s, ok := registry[day]
+	return s, ok
+}
+
+// Days returns all the registered day numbers in ascending order
+func Days() []int {
+	days := make([]int, 0, len(registry))
+	for day := range registry {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
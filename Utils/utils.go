@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"math/big"
 	"strconv"
 )
 
@@ -54,6 +56,48 @@ func LCM(numbers ...int) int {
 	return lcm
 }
 
+// solve x = residues[i] mod moduli[i] for every i, the moduli do not need to be coprime.
+// Return the smallest x >= 0 and the LCM of the moduli, x is unique modulo this LCM.
+// An error is returned when the congruences cannot all be true at the same time
+func CRT(residues, moduli []int) (int, int, error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, fmt.Errorf("CRT needs one modulus per residue, got %d residues and %d moduli", len(residues), len(moduli))
+	}
+
+	//big numbers avoid overflows in the products, the result must still fit in an int
+	x, m := big.NewInt(0), big.NewInt(1)
+	for i := range residues {
+		if moduli[i] <= 0 {
+			return 0, 0, fmt.Errorf("CRT modulus must be positive, got %d", moduli[i])
+		}
+		a, n := big.NewInt(int64(residues[i])), big.NewInt(int64(moduli[i]))
+		a.Mod(a, n)
+
+		//x + m*k = a (mod n) has a solution only if gcd(m, n) divides a - x
+		g, inv := new(big.Int), new(big.Int)
+		g.GCD(inv, nil, m, n)
+		diff := new(big.Int).Sub(a, x)
+		if new(big.Int).Mod(diff, g).Sign() != 0 {
+			return 0, 0, fmt.Errorf("no solution: x = %d mod %d contradicts the previous congruences", residues[i], moduli[i])
+		}
+
+		//k = (a - x)/g * inverse(m/g) mod n/g
+		nOverG := new(big.Int).Div(n, g)
+		k := new(big.Int).Div(diff, g)
+		k.Mul(k, inv)
+		k.Mod(k, nOverG)
+
+		x.Add(x, new(big.Int).Mul(m, k))
+		m.Mul(m, nOverG)
+		x.Mod(x, m)
+	}
+
+	if !m.IsInt64() {
+		return 0, 0, fmt.Errorf("CRT modulus %v overflows an int", m)
+	}
+	return int(x.Int64()), int(m.Int64()), nil
+}
+
 // detect overlap between a range AA' and a range BB' using their start/end as coordinates
 func CheckIntersect(aStart int, aEnd int, bStart int, bEnd int) (int, string) {
 	if aEnd < bStart || bEnd < aStart {
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// answerBook holds the expected answers of the puzzles.
+// Every player gets a different input so the answers are keyed by the
+// SHA-256 of the input file, one JSON file can hold the answers of the whole team
+type answerBook map[string]answerEntry
+
+// answerEntry is the expected answer of each part for one input,
+// answers are kept as text so that any kind of answer can be stored
+type answerEntry struct {
+	Day   int    `json:"day"`
+	Part1 string `json:"part1,omitempty"`
+	Part2 string `json:"part2,omitempty"`
+}
+
+// get the key of an input in the answer book
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// read the answer book from a JSON file, a missing file is an empty book
+func loadAnswers(path string) (answerBook, error) {
+	book := answerBook{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &book); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// write the answer book to a JSON file, keys are sorted by encoding/json
+// so the file stays stable between two records
+func (b answerBook) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// return the expected answer for a part of an input, false if there is none
+func (b answerBook) expected(key string, part int) (string, bool) {
+	entry, ok := b[key]
+	if !ok {
+		return "", false
+	}
+	answer := entry.Part1
+	if part == 2 {
+		answer = entry.Part2
+	}
+	return answer, answer != ""
+}
+
+// add the answer of a part if the input has no expectation for it yet.
+// An existing expectation is never replaced, a wrong one has to be fixed by hand
+func (b answerBook) record(key string, day, part int, answer string) bool {
+	if _, ok := b.expected(key, part); ok {
+		return false
+	}
+	entry := b[key]
+	entry.Day = day
+	if part == 1 {
+		entry.Part1 = answer
+	} else {
+		entry.Part2 = answer
+	}
+	b[key] = entry
+	return true
+}
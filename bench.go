@@ -0,0 +1,97 @@
+package main
+
+import (
+	"AdventOfCode/Utils/solver"
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// measure is the cost of one run of a part
+type measure struct {
+	elapsed time.Duration
+	alloc   uint64
+}
+
+// stats sums up the measures of all the runs of a part, durations are in nanoseconds in JSON.
+// Alloc is the median of the bytes allocated by one run, it includes the parsing of the input
+type stats struct {
+	Runs   int           `json:"runs"`
+	Min    time.Duration `json:"min_ns"`
+	Median time.Duration `json:"median_ns"`
+	P95    time.Duration `json:"p95_ns"`
+	Alloc  uint64        `json:"alloc_bytes"`
+}
+
+// run a part once and measure its wall time and the bytes it allocated.
+// The allocation counter is global to the process, it is only exact when no other part runs at the same time
+func measurePart(ctx context.Context, day, part int, data []byte) (solver.Result, measure) {
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	result := runPart(ctx, day, part, data)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return result, measure{elapsed: elapsed, alloc: after.TotalAlloc - before.TotalAlloc}
+}
+
+// get the min, median and 95th percentile of the measures
+func summarize(measures []measure) stats {
+	if len(measures) == 0 {
+		return stats{}
+	}
+
+	times := make([]time.Duration, len(measures))
+	allocs := make([]uint64, len(measures))
+	for i, m := range measures {
+		times[i] = m.elapsed
+		allocs[i] = m.alloc
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	sort.Slice(allocs, func(i, j int) bool { return allocs[i] < allocs[j] })
+
+	return stats{
+		Runs:   len(measures),
+		Min:    times[0],
+		Median: times[len(times)/2],
+		P95:    times[percentileIndex(len(times), 95)],
+		Alloc:  allocs[len(allocs)/2],
+	}
+}
+
+// index of the p-th percentile in a sorted slice of n elements (nearest rank)
+func percentileIndex(n, p int) int {
+	rank := (n*p + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return rank - 1
+}
+
+func (s stats) String() string {
+	if s.Runs == 0 {
+		return ""
+	}
+	if s.Runs == 1 {
+		return fmt.Sprintf("in %v, %s allocated", s.Min, formatBytes(s.Alloc))
+	}
+	return fmt.Sprintf("min %v, median %v, p95 %v over %d runs, %s allocated", s.Min, s.Median, s.P95, s.Runs, formatBytes(s.Alloc))
+}
+
+// print a number of bytes with the closest unit
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -1,87 +1,312 @@
 package main
 
 import (
-	"AdventOfCode/Day25"
+	_ "AdventOfCode/Day1"
+	_ "AdventOfCode/Day10"
+	_ "AdventOfCode/Day11"
+	_ "AdventOfCode/Day12"
+	_ "AdventOfCode/Day13"
+	_ "AdventOfCode/Day14"
+	_ "AdventOfCode/Day15"
+	_ "AdventOfCode/Day16"
+	_ "AdventOfCode/Day17"
+	_ "AdventOfCode/Day18"
+	_ "AdventOfCode/Day19"
+	_ "AdventOfCode/Day2"
+	_ "AdventOfCode/Day20"
+	_ "AdventOfCode/Day21"
+	_ "AdventOfCode/Day22"
+	_ "AdventOfCode/Day23"
+	_ "AdventOfCode/Day24"
+	_ "AdventOfCode/Day25"
+	_ "AdventOfCode/Day3"
+	_ "AdventOfCode/Day4"
+	_ "AdventOfCode/Day5"
+	_ "AdventOfCode/Day6"
+	_ "AdventOfCode/Day7"
+	_ "AdventOfCode/Day8"
+	_ "AdventOfCode/Day9"
+	"AdventOfCode/Utils/gopool"
+	"AdventOfCode/Utils/solver"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// usage:
+//
+//	go run . -all                        run every registered day
+//	go run . -list                       list the registered days
+//	go run . -day 5,17                   run only days 5 and 17
+//	go run . -day 8 -part 2              run only the second part of day 8
+//	go run . -day 8 -input other.txt     run day 8 on another input file
+//	cat other.txt | go run . -day 8 -input -
+//	go run . -all -record                save the answers that have no expectation yet
+//	go run . -all -parallel 8            run up to 8 parts at the same time
+//	go run . -day 17 -bench 10           run each part 10 times and report min, median and p95
+//	go run . -all -format junit          write the report as JSON, JUnit XML or TAP instead of text
+//	go run . -all -timeout 30s           stop any part running for more than 30s
 func main() {
+	dayFlag := flag.String("day", "", "comma separated list of days to run, ex: 5 or 5,17")
+	partFlag := flag.Int("part", 0, "part to run (1 or 2), 0 runs both parts")
+	inputFlag := flag.String("input", "", "puzzle input file, - reads stdin (default ./DayN/Ressources/dayN_input.txt)")
+	allFlag := flag.Bool("all", false, "run every registered day")
+	listFlag := flag.Bool("list", false, "list the registered days")
+	answersFlag := flag.String("answers", "answers.json", "JSON file of the expected answers, keyed by the SHA-256 of the input")
+	recordFlag := flag.Bool("record", false, "save the answers of the inputs that have no expectation yet")
+	parallelFlag := flag.Int("parallel", 1, "number of parts running at the same time")
+	benchFlag := flag.Int("bench", 1, "run each part N times and report the min, median and p95 wall time")
+	formatFlag := flag.String("format", "text", "report format: text, json, junit or tap")
+	timeoutFlag := flag.Duration("timeout", 0, "stop each part after this long and report it as TIMEOUT, ex: 30s (0 means no timeout)")
+	flag.Parse()
 
-	results := [][2]int{
-		{-1, -1}, //Day1.Day1(),
-		{-1, -1}, //Day2.Day2(),
-		{-1, -1}, //Day3.Day3(),
-		{-1, -1}, //Day4.Day4(),
-		{-1, -1}, //Day5.Day5(), //slow
-		{-1, -1}, //Day6.Day6(),
-		{-1, -1}, //Day7.Day7(),
-		{-1, -1}, //Day8.Day8(),
-		{-1, -1}, //Day9.Day9(),
-		{-1, -1}, //Day10.Day10(),
-		{-1, -1}, //Day11.Day11(),
-		{-1, 0},  //Day12.Day12(), //only p1 found so far will come back later for p2
-		{-1, -1}, //Day13.Day13(),
-		{-1, -1}, //Day14.Day14(),
-		{-1, -1}, //Day15.Day15(),
-		{-1, -1}, //Day16.Day16(), //slow
-		{-1, -1}, //Day17.Day17(), //slow
-		{-1, -1}, //Day18.Day18(),
-		{-1, 0},  //Day19.Day19(), //only p1 found so far will come back later for p2
-		{-1, 0},  //Day20.Day20(), //only p1 found so far will come back later for p2
-		{-1, -1}, //Day21.Day21(),
-		{-1, -1}, //Day22.Day22(), //slightly slow but very fine
-		{-1, -1}, //Day23.Day23(), //p2 is brutforce can be optimised by mapping itersect & computing length between them in a smaller graph
-		{-1, -1}, //Day24.Day24(), //p1 done , p2 found answer on reddit, it's math not prog
-		Day25.Day25(),
-	}
-
-	testResults(results)
+	if *listFlag {
+		for _, day := range solver.Days() {
+			fmt.Println("Day", day)
+		}
+		return
+	}
+
+	days, err := selectDays(*dayFlag, *allFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *partFlag < 0 || *partFlag > 2 {
+		fmt.Fprintln(os.Stderr, "-part must be 1, 2 or 0 for both parts")
+		os.Exit(2)
+	}
+
+	if *parallelFlag < 1 {
+		fmt.Fprintln(os.Stderr, "-parallel must be at least 1")
+		os.Exit(2)
+	}
+
+	if *benchFlag < 1 {
+		fmt.Fprintln(os.Stderr, "-bench must be at least 1")
+		os.Exit(2)
+	}
+
+	if *timeoutFlag < 0 {
+		fmt.Fprintln(os.Stderr, "-timeout cannot be negative")
+		os.Exit(2)
+	}
+
+	if *formatFlag != "text" && *formatFlag != "json" && *formatFlag != "junit" && *formatFlag != "tap" {
+		fmt.Fprintln(os.Stderr, "-format must be text, json, junit or tap")
+		os.Exit(2)
+	}
+
+	if *inputFlag != "" && len(days) != 1 {
+		fmt.Fprintln(os.Stderr, "-input can only be used when running a single day")
+		os.Exit(2)
+	}
+
+	book, err := loadAnswers(*answersFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cannot read the answers file:", err)
+		os.Exit(2)
+	}
+
+	//days and parts that are not run are reported as skipped (ran is false)
+	//a failed day keeps its error result and the run goes on with the next days
+	//keys hold the fingerprint of the input each day ran on
+	results := make([][2]solver.Result, 25)
+	ran := make([][2]bool, 25)
+	keys := make([]string, 25)
+	timings := make([][2]stats, 25)
+
+	//read every input first, both parts of a day run on the same data
+	inputs := make([][]byte, 25)
+	for _, day := range days {
+		data, err := readInput(day, *inputFlag)
+		if err != nil {
+			for part := 1; part <= 2; part++ {
+				if *partFlag == 0 || *partFlag == part {
+					results[day-1][part-1], ran[day-1][part-1] = solver.Error(err), true
+				}
+			}
+			continue
+		}
+		inputs[day-1] = data
+		keys[day-1] = fingerprint(data)
+	}
+
+	//each part is a task of the pool and only writes to its own slot,
+	//so the results are the same whatever order the tasks finish in
+	pool := gopool.NewPool(*parallelFlag)
+	for _, day := range days {
+		if inputs[day-1] == nil {
+			continue
+		}
+		for part := 1; part <= 2; part++ {
+			if *partFlag != 0 && *partFlag != part {
+				continue
+			}
+			pool.Add(1)
+			go func(day, part int) {
+				defer pool.Done()
+				ran[day-1][part-1] = true
+				measures := []measure{}
+				for run := 0; run < *benchFlag; run++ {
+					result, m := runWithTimeout(day, part, inputs[day-1], *timeoutFlag)
+					results[day-1][part-1] = result
+					if result.Kind() == solver.KindError {
+						break
+					}
+					measures = append(measures, m)
+				}
+				timings[day-1][part-1] = summarize(measures)
+			}(day, part)
+		}
+	}
+	pool.Wait()
+
+	//report the failures in day order once everything is done
+	failed := false
+	for _, day := range days {
+		for part := 1; part <= 2; part++ {
+			if err := results[day-1][part-1].Err(); err != nil {
+				fmt.Fprintln(os.Stderr, "Day", day, "part", part, "failed:", err)
+				failed = true
+			}
+		}
+	}
+
+	records := buildRecords(results, ran, keys, book, timings)
+	if err := writeReport(os.Stdout, *formatFlag, records); err != nil {
+		fmt.Fprintln(os.Stderr, "cannot write the report:", err)
+		failed = true
+	}
+
+	if *recordFlag {
+		if err := recordAnswers(records, book, *answersFlag); err != nil {
+			fmt.Fprintln(os.Stderr, "cannot record the answers:", err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run one part of a day on its own reader of the input
+func runPart(ctx context.Context, day, part int, data []byte) solver.Result {
+	s, _ := solver.Get(day)
+	if part == 1 {
+		return s.Part1(ctx, bytes.NewReader(data))
+	}
+	return s.Part2(ctx, bytes.NewReader(data))
+}
+
+// measure one run of a part that is cancelled after timeout, 0 means no timeout.
+// The runner does not wait for a part that ignores its context past the deadline,
+// the part is left behind and reported as a timeout
+func runWithTimeout(day, part int, data []byte, timeout time.Duration) (solver.Result, measure) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result solver.Result
+		m      measure
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, m := measurePart(ctx, day, part, data)
+		done <- outcome{result, m}
+	}()
+
+	var o outcome
+	select {
+	case o = <-done:
+	case <-ctx.Done():
+		o = outcome{solver.Error(ctx.Err()), measure{elapsed: timeout}}
+	}
+
+	if err := o.result.Err(); errors.Is(err, context.DeadlineExceeded) {
+		o.result = solver.Error(fmt.Errorf("timeout after %v: %w", timeout, err))
+	}
+	return o.result, o.m
 }
 
-func testResults(result [][2]int) {
-	expectedResults := [][2]int{
-		{54916, 54728},
-		{2176, 63700},
-		{546312, 87449461},
-		{26426, 6227972},
-		{322500873, 108956227},
-		{2374848, 39132886},
-		{253866470, 254494947},
-		{17873, 15746133679061},
-		{1987402313, 900},
-		{6886, 371},
-		{10289334, 649862989626},
-		{7047, -1},
-		{35521, 34795},
-		{106517, 79723},
-		{502139, 284132},
-		{7927, 8246},
-		{785, 922},
-		{74074, 112074045986829},
-		{432788, -1},
-		{788848550, -1},
-		{3600, 599763113936220},
-		{441, 80778},
-		{2430, 6534},
-		{14672, 646810057104753},
-		{-1, -1},
-	}
-
-	for i := 0; i < len(expectedResults); i++ {
-		if expectedResults[i][0] == result[i][0] {
-			fmt.Println("\033[32mDay: ", i+1, ", Part: 1, PASS result :", result[i][0], " matching expected result ", expectedResults[i][0], "\033[0m")
-		} else if result[i][0] == -1 {
-			fmt.Println("Day: ", i+1, ", Part: 1, SKIPED expected result ", expectedResults[i][0])
-		} else {
-			fmt.Println("\033[31mDay: ", i+1, ", Part: 1, FAIL result :", result[i][0], " not matching expected result ", expectedResults[i][0], "\033[0m")
+// read the -day and -all flags and return the list of days to run
+func selectDays(dayList string, all bool) ([]int, error) {
+	if all && dayList != "" {
+		return nil, errors.New("-day and -all cannot be used together")
+	}
+	if all {
+		return solver.Days(), nil
+	}
+	if dayList == "" {
+		return nil, errors.New("select the days to run with -day or -all")
+	}
+
+	//a day listed twice runs once, each day owns a single slot of the results
+	days := []int{}
+	seen := map[int]bool{}
+	for _, field := range strings.Split(dayList, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid day number: %q", field)
 		}
+		if _, ok := solver.Get(day); !ok {
+			return nil, fmt.Errorf("no solver registered for day %d", day)
+		}
+		if seen[day] {
+			continue
+		}
+		seen[day] = true
+		days = append(days, day)
+	}
+	return days, nil
+}
 
-		if expectedResults[i][1] == result[i][1] {
-			fmt.Println("\033[32mDay: ", i+1, ", Part: 2, PASS result :", result[i][1], " matching expected result ", expectedResults[i][1], "\033[0m")
-		} else if result[i][1] == -1 {
-			fmt.Println("Day: ", i+1, ", Part: 2, SKIPED expected result ", expectedResults[i][1])
-		} else {
-			fmt.Println("\033[31mDay: ", i+1, ", Part: 2, FAIL result :", result[i][1], " not matching expected result ", expectedResults[i][1], "\033[0m")
+// load the whole puzzle input of a day in memory so that both parts can read it.
+// path "-" reads stdin, an empty path falls back to the day Ressources folder
+func readInput(day int, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	if path == "" {
+		path = fmt.Sprintf("./Day%d/Ressources/day%d_input.txt", day, day)
+	}
+	return os.ReadFile(path)
+}
+
+// save the answers of the parts that ran fine and had no expectation yet.
+// Unsolved parts are never recorded. Messages go to stderr to keep stdout for the report
+func recordAnswers(records []record, book answerBook, path string) error {
+	count := 0
+	for _, r := range records {
+		if r.Status != statusUnchecked {
+			continue
 		}
+		if book.record(r.Input, r.Day, r.Part, r.Answer) {
+			count++
+		}
+	}
+
+	if count == 0 {
+		fmt.Fprintln(os.Stderr, "no new answer to record")
+		return nil
+	}
+	if err := book.save(path); err != nil {
+		return err
 	}
+	fmt.Fprintln(os.Stderr, "recorded", count, "new answers in", path)
+	return nil
 }
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"AdventOfCode/Utils/solver"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// status of a part in the run reports
+const (
+	statusPass      = "pass"      // the answer matches the expected answer of the input
+	statusFail      = "fail"      // the answer does not match the expected answer of the input
+	statusSkipped   = "skipped"   // the part was not run
+	statusUnsolved  = "unsolved"  // the part ran but is not solved yet
+	statusUnchecked = "unchecked" // the part ran but there is no expected answer for its input
+	statusError     = "error"     // the input could not be read or parsed, or the part failed
+	statusTimeout   = "timeout"   // the part did not finish before the -timeout
+)
+
+// record is the report of one part of one day,
+// Kind tells if the answer is a number or a text
+type record struct {
+	Day      int    `json:"day"`
+	Part     int    `json:"part"`
+	Input    string `json:"input,omitempty"`
+	Answer   string `json:"answer,omitempty"`
+	Kind     string `json:"kind,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Timing   *stats `json:"timing,omitempty"`
+}
+
+// build the records of the 25 days in day order, the days that were not run are skipped
+func buildRecords(results [][2]solver.Result, ran [][2]bool, keys []string, book answerBook, timings [][2]stats) []record {
+	records := []record{}
+	for i := 0; i < len(results); i++ {
+		for part := 1; part <= 2; part++ {
+			rec := record{Day: i + 1, Part: part, Input: keys[i]}
+			rec.Expected, _ = book.expected(keys[i], part)
+			r := results[i][part-1]
+
+			if !ran[i][part-1] {
+				rec.Status = statusSkipped
+				records = append(records, rec)
+				continue
+			}
+			if err := r.Err(); err != nil {
+				rec.Status = statusError
+				if errors.Is(err, context.DeadlineExceeded) {
+					rec.Status = statusTimeout
+				}
+				rec.Error = err.Error()
+				records = append(records, rec)
+				continue
+			}
+
+			t := timings[i][part-1]
+			rec.Timing = &t
+			if r.Kind() == solver.KindUnsolved {
+				rec.Status = statusUnsolved
+				records = append(records, rec)
+				continue
+			}
+
+			rec.Answer = r.String()
+			rec.Kind = r.Kind().String()
+			if rec.Expected == "" {
+				rec.Status = statusUnchecked
+			} else if rec.Expected == rec.Answer {
+				rec.Status = statusPass
+			} else {
+				rec.Status = statusFail
+			}
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+// write the records in one of the supported formats: text, json, junit or tap
+func writeReport(w io.Writer, format string, records []record) error {
+	switch format {
+	case "text":
+		return writeText(w, records)
+	case "json":
+		return writeJSON(w, records)
+	case "junit":
+		return writeJUnit(w, records)
+	case "tap":
+		return writeTAP(w, records)
+	}
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+// print the result of each part against the expected answer of its input, with ANSI colors
+func writeText(w io.Writer, records []record) error {
+	for _, r := range records {
+		var err error
+		switch r.Status {
+		case statusError:
+			_, err = fmt.Fprintln(w, "\033[31mDay: ", r.Day, ", Part:", r.Part, ", ERROR", r.Error, "\033[0m")
+		case statusTimeout:
+			_, err = fmt.Fprintln(w, "\033[31mDay: ", r.Day, ", Part:", r.Part, ", TIMEOUT", r.Error, "\033[0m")
+		case statusSkipped:
+			_, err = fmt.Fprintln(w, "Day: ", r.Day, ", Part:", r.Part, ", SKIPED")
+		case statusUnsolved:
+			_, err = fmt.Fprintln(w, "\033[33mDay: ", r.Day, ", Part:", r.Part, ", UNSOLVED not solved yet,", r.Timing, "\033[0m")
+		case statusUnchecked:
+			_, err = fmt.Fprintln(w, "\033[33mDay: ", r.Day, ", Part:", r.Part, ", NO EXPECTATION result :", r.answerText(), " no expected result for this input,", r.Timing, "\033[0m")
+		case statusPass:
+			_, err = fmt.Fprintln(w, "\033[32mDay: ", r.Day, ", Part:", r.Part, ", PASS result :", r.answerText(), " matching expected result ", r.Expected, ",", r.Timing, "\033[0m")
+		case statusFail:
+			_, err = fmt.Fprintln(w, "\033[31mDay: ", r.Day, ", Part:", r.Part, ", FAIL result :", r.answerText(), " not matching expected result ", r.Expected, ",", r.Timing, "\033[0m")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the answer as printed in the text report, text answers are quoted so that
+// they cannot be mistaken for a number
+func (r record) answerText() string {
+	if r.Kind == solver.KindText.String() {
+		return strconv.Quote(r.Answer)
+	}
+	return r.Answer
+}
+
+// write the records as an indented JSON array
+func writeJSON(w io.Writer, records []record) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// JUnit XML layout: one test suite per day, one test case per part
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     float64      `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     float64     `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// write the records as a JUnit XML report, unsolved parts are reported as skipped
+// and the parts with no expected answer as passed
+func writeJUnit(w io.Writer, records []record) error {
+	report := junitSuites{Name: "AdventOfCode"}
+	for _, r := range records {
+		if len(report.Suites) == 0 || report.Suites[len(report.Suites)-1].Name != fmt.Sprint("Day ", r.Day) {
+			report.Suites = append(report.Suites, junitSuite{Name: fmt.Sprint("Day ", r.Day)})
+		}
+		suite := &report.Suites[len(report.Suites)-1]
+
+		c := junitCase{
+			Name:      fmt.Sprint("Part ", r.Part),
+			ClassName: fmt.Sprint("Day", r.Day),
+		}
+		if r.Timing != nil {
+			c.Time = r.Timing.Median.Seconds()
+			c.SystemOut = fmt.Sprint(r.Timing)
+			if r.Answer != "" {
+				c.SystemOut = fmt.Sprint("answer: ", r.answerText(), ", ", r.Timing)
+			}
+		}
+
+		switch r.Status {
+		case statusFail:
+			c.Failure = &junitMessage{fmt.Sprintf("expected %s, got %s", r.Expected, r.answerText())}
+			suite.Failures++
+		case statusError, statusTimeout:
+			c.Error = &junitMessage{r.Error}
+			suite.Errors++
+		case statusSkipped:
+			c.Skipped = &junitMessage{"not run"}
+			suite.Skipped++
+		case statusUnsolved:
+			c.Skipped = &junitMessage{"not solved yet"}
+			suite.Skipped++
+		case statusUnchecked:
+			c.SystemOut += ", no expected answer for this input"
+		}
+
+		suite.Cases = append(suite.Cases, c)
+		suite.Tests++
+		suite.Time += c.Time
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+		report.Time += suite.Time
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// write the records as a TAP version 13 stream, unsolved parts are TODO tests
+func writeTAP(w io.Writer, records []record) error {
+	if _, err := fmt.Fprintf(w, "TAP version 13\n1..%d\n", len(records)); err != nil {
+		return err
+	}
+
+	for i, r := range records {
+		name := fmt.Sprintf("Day %d part %d", r.Day, r.Part)
+		var line string
+		switch r.Status {
+		case statusPass:
+			line = fmt.Sprintf("ok %d - %s: %s", i+1, name, r.answerText())
+		case statusUnchecked:
+			line = fmt.Sprintf("ok %d - %s: %s, no expected answer for this input", i+1, name, r.answerText())
+		case statusFail:
+			line = fmt.Sprintf("not ok %d - %s: %s, expected %s", i+1, name, r.answerText(), r.Expected)
+		case statusError:
+			line = fmt.Sprintf("not ok %d - %s", i+1, name)
+		case statusTimeout:
+			line = fmt.Sprintf("not ok %d - %s: timeout", i+1, name)
+		case statusSkipped:
+			line = fmt.Sprintf("ok %d - %s # SKIP not run", i+1, name)
+		case statusUnsolved:
+			line = fmt.Sprintf("not ok %d - %s # TODO not solved yet", i+1, name)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+
+		//YAML diagnostic block with the details of the part
+		if r.Status == statusSkipped {
+			continue
+		}
+		diagnostic := "  ---\n"
+		if r.Error != "" {
+			diagnostic += fmt.Sprintf("  error: %q\n", r.Error)
+		}
+		if r.Timing != nil {
+			diagnostic += fmt.Sprintf("  runs: %d\n  min_ns: %d\n  median_ns: %d\n  p95_ns: %d\n  alloc_bytes: %d\n",
+				r.Timing.Runs, r.Timing.Min, r.Timing.Median, r.Timing.P95, r.Timing.Alloc)
+		}
+		diagnostic += "  ...\n"
+		if _, err := io.WriteString(w, diagnostic); err != nil {
+			return err
+		}
+	}
+	return nil
+}